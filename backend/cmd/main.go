@@ -1,17 +1,43 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"myspace/backend/internal/cache"
+	"myspace/backend/internal/commands"
 	"myspace/backend/internal/config"
 	"myspace/backend/internal/database"
 	"myspace/backend/internal/handlers"
+	"myspace/backend/internal/invoices"
+	"myspace/backend/internal/live"
+	"myspace/backend/internal/logging"
 	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/scheduler"
+	"myspace/backend/internal/syncer"
+	"myspace/backend/internal/trackers"
+	"os"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	cfg := config.Load()
+	if len(os.Args) > 1 {
+		if err := commands.Run(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	logger := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format, cfg.Secrets.Values())
+	slog.SetDefault(logger)
 	
 	db, err := database.Connect(cfg.Database.Path)
 	if err != nil {
@@ -22,18 +48,85 @@ func main() {
 		log.Fatal("Failed to migrate database:", err)
 	}
 	
-	trackersRepo := repositories.NewTrackersRepository(cfg)
+	trackersRepo := repositories.NewTrackersRepository(cfg, logger)
+	tracksRepo := repositories.NewTracksRepository(db)
+	
+	syncEngine := syncer.NewEngine(trackersRepo.Remote(), tracksRepo, cfg.Location, logger)
+	
+	switch cfg.Cache.Driver {
+	case "memory":
+		trackersRepo.UseCache(cache.NewMemoryStore(cfg.Cache.MaxEntries), cacheTTLs(cfg))
+	case "sqlite":
+		trackersRepo.UseCache(cache.NewSQLiteStore(db, logger), cacheTTLs(cfg))
+	}
+	
+	if cfg.Sync.ReadFromStore {
+		trackersRepo.ServeFrom(tracksRepo)
+	}
+	
+	trackersRepo.UseFallback(repositories.NewSnapshotsRepository(db))
+	
+	settingsRepo := repositories.NewSettingsRepository(db)
+	preferencesRepo := repositories.NewPreferencesRepository(settingsRepo, repositories.DefaultPreferences(cfg.Location))
+	leaveRepo := repositories.NewLeaveRepository(db)
+	schedulesRepo := repositories.NewSchedulesRepository(db)
+	periodsRepo := repositories.NewPeriodsRepository(db)
+	ratesRepo := repositories.NewRatesRepository(db)
+	invoicesRepo := repositories.NewInvoicesRepository(db)
+	holidaysRepo := repositories.NewHolidaysRepository(db, preferencesRepo, leaveRepo, schedulesRepo)
+	overtimeRepo := repositories.NewOvertimeRepository(db, trackersRepo, holidaysRepo, preferencesRepo)
+	versionsRepo := repositories.NewVersionsRepository(db)
+	
+	jobs := scheduler.New(settingsRepo, cfg.Location, logger)
+	for _, registration := range []struct {
+		name string
+		spec string
+		run  scheduler.JobFunc
+	}{
+		{"sync", cfg.Sync.Schedule, scheduler.SyncJob(syncEngine, trackersRepo, cfg.Sync.Months)},
+		{"warmup", cfg.Jobs.WarmupSchedule, scheduler.WarmupJob(trackersRepo, cfg.Location)},
+		{"report", cfg.Jobs.ReportSchedule, scheduler.ReportJob(trackersRepo, cfg.Location, cfg.Jobs.ReportsDir)},
+	} {
+		if err := jobs.Add(registration.name, registration.spec, registration.run); err != nil {
+			log.Fatal("Failed to schedule job:", err)
+		}
+	}
+	jobs.Start(context.Background())
+	
+	renderer, err := invoices.NewRenderer(cfg.Invoices.TemplatesDir, cfg.Invoices.Issuer)
+	if err != nil {
+		log.Fatal("Failed to load invoice templates:", err)
+	}
+	
+	poller := live.NewPoller(trackersRepo, holidaysRepo, cfg.Live.Interval, logger)
+	go poller.Run(context.Background())
 	
-	todayHandler := handlers.NewTodayHandler(trackersRepo)
-	projectsHandler := handlers.NewProjectsHandler(trackersRepo)
-	calendarHandler := handlers.NewCalendarHandler(trackersRepo)
+	todayHandler := handlers.NewTodayHandler(trackersRepo, preferencesRepo, holidaysRepo, overtimeRepo, ratesRepo, versionsRepo)
+	projectsHandler := handlers.NewProjectsHandler(trackersRepo, preferencesRepo, holidaysRepo, ratesRepo, versionsRepo)
+	calendarHandler := handlers.NewCalendarHandler(trackersRepo, preferencesRepo, holidaysRepo, versionsRepo)
+	weekHandler := handlers.NewWeekHandler(trackersRepo, holidaysRepo)
+	reportsHandler := handlers.NewReportsHandler(trackersRepo)
+	cacheHandler := handlers.NewCacheHandler(trackersRepo)
+	jobsHandler := handlers.NewJobsHandler(jobs)
+	liveHandler := handlers.NewLiveHandler(poller)
+	settingsHandler := handlers.NewSettingsHandler(preferencesRepo)
+	holidaysHandler := handlers.NewHolidaysHandler(holidaysRepo, preferencesRepo)
+	leaveHandler := handlers.NewLeaveHandler(leaveRepo, holidaysRepo, preferencesRepo)
+	overtimeHandler := handlers.NewOvertimeHandler(overtimeRepo)
+	schedulesHandler := handlers.NewSchedulesHandler(schedulesRepo)
+	periodsHandler := handlers.NewPeriodsHandler(periodsRepo, trackersRepo, preferencesRepo, holidaysRepo, versionsRepo)
+	ratesHandler := handlers.NewRatesHandler(ratesRepo, trackersRepo, preferencesRepo)
+	invoicesHandler := handlers.NewInvoicesHandler(invoicesRepo, trackersRepo, ratesRepo, periodsRepo, preferencesRepo, renderer, cfg.Invoices.DueDays)
+	webhooksHandler := handlers.NewWebhooksHandler(trackersRepo, syncEngine, logger)
+	yearHandler := handlers.NewYearHandler(trackersRepo, holidaysRepo)
 	
 	r := gin.Default()
 	
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Timezone, If-None-Match, If-Modified-Since")
+		c.Header("Access-Control-Expose-Headers", "ETag, Last-Modified")
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -43,6 +136,8 @@ func main() {
 		c.Next()
 	})
 	
+	r.Use(handlers.Timezone(preferencesRepo.Location))
+	
 	r.GET("/", func(c *gin.Context) {
 		c.Redirect(302, "/today")
 	})
@@ -54,6 +149,76 @@ func main() {
 	r.GET("/:year/:month/projects", projectsHandler.Index)
 	r.GET("/:year/:month/calendar", calendarHandler.Index)
 	
-	log.Printf("Server starting on port %s", cfg.Port)
+	r.GET("/week", weekHandler.Redirect)
+	r.GET("/:year/week/:week", weekHandler.Index)
+	
+	r.GET("/reports", reportsHandler.Index)
+	
+	r.DELETE("/cache", cacheHandler.Invalidate)
+	r.DELETE("/cache/:source", cacheHandler.Invalidate)
+	
+	r.GET("/settings", settingsHandler.Index)
+	r.PUT("/settings", settingsHandler.Update)
+	
+	r.GET("/holidays", holidaysHandler.Index)
+	r.GET("/holidays/countries", holidaysHandler.Countries)
+	r.POST("/holidays/import", holidaysHandler.Import)
+	r.DELETE("/holidays/:date", holidaysHandler.Delete)
+	
+	r.GET("/leave", leaveHandler.Index)
+	r.POST("/leave", leaveHandler.Create)
+	r.PUT("/leave/:id", leaveHandler.Update)
+	r.DELETE("/leave/:id", leaveHandler.Delete)
+	
+	r.GET("/overtime", overtimeHandler.Index)
+	r.POST("/overtime/adjustments", overtimeHandler.AddAdjustment)
+	r.DELETE("/overtime/adjustments/:id", overtimeHandler.DeleteAdjustment)
+	
+	r.GET("/schedules", schedulesHandler.Index)
+	r.POST("/schedules", schedulesHandler.Create)
+	r.PUT("/schedules/:id", schedulesHandler.Update)
+	r.DELETE("/schedules/:id", schedulesHandler.Delete)
+	
+	r.GET("/periods", periodsHandler.Index)
+	r.POST("/periods", periodsHandler.Create)
+	r.PUT("/periods/:id", periodsHandler.Update)
+	r.DELETE("/periods/:id", periodsHandler.Delete)
+	r.GET("/periods/:id/current", periodsHandler.Redirect)
+	r.GET("/periods/:id/:date/projects", periodsHandler.Projects)
+	r.GET("/periods/:id/:date/calendar", periodsHandler.Calendar)
+	
+	r.GET("/rates", ratesHandler.Index)
+	r.POST("/rates", ratesHandler.Create)
+	r.PUT("/rates/:id", ratesHandler.Update)
+	r.DELETE("/rates/:id", ratesHandler.Delete)
+	
+	r.GET("/invoices", invoicesHandler.Index)
+	r.POST("/invoices", invoicesHandler.Create)
+	r.GET("/invoices/:id", invoicesHandler.Show)
+	r.DELETE("/invoices/:id", invoicesHandler.Delete)
+	r.PUT("/invoices/:id/status", invoicesHandler.Status)
+	r.GET("/invoices/:id/html", invoicesHandler.HTML)
+	r.GET("/invoices/:id/pdf", invoicesHandler.PDF)
+	
+	r.GET("/live", liveHandler.Stream)
+	
+	r.POST("/webhooks/:source", webhooksHandler.Receive)
+	
+	r.GET("/jobs", jobsHandler.Index)
+	r.POST("/jobs/:name/run", jobsHandler.Run)
+	
+	r.GET("/:year", yearHandler.Index)
+	
+	logger.Info("server starting", "port", cfg.Port)
 	r.Run(":" + cfg.Port)
-}
\ No newline at end of file
+}
+
+func cacheTTLs(cfg *config.Config) trackers.CacheTTLs {
+	return trackers.CacheTTLs{
+		Running: cfg.Cache.RunningTTL,
+		Current: cfg.Cache.CurrentTTL,
+		Past:    cfg.Cache.PastTTL,
+		Closed:  cfg.Cache.ClosedTTL,
+		UserID:  24 * time.Hour,
+	}
+}
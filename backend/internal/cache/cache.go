@@ -0,0 +1,20 @@
+package cache
+
+import "time"
+
+// Store keeps opaque values under string keys. A ttl of zero means the
+// value never expires.
+type Store interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	DeletePrefix(prefix string)
+}
+
+func expiresAt(ttl time.Duration) *time.Time {
+	if ttl <= 0 {
+		return nil
+	}
+
+	t := time.Now().Add(ttl)
+	return &t
+}
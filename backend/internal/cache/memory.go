@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt *time.Time
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return e.expiresAt != nil && now.After(*e.expiresAt)
+}
+
+// MemoryStore keeps at most maxEntries values, dropping expired ones first
+// and then the least recently used, so keys built from arbitrary ranges
+// can't grow it without limit.
+type MemoryStore struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List
+	maxEntries int
+}
+
+// NewMemoryStore returns a store holding up to maxEntries values; zero or
+// less means no limit.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+	}
+}
+
+func (m *MemoryStore) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if entry.expired(time.Now()) {
+		m.remove(element)
+		return nil, false
+	}
+
+	m.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (m *MemoryStore) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{key: key, value: value, expiresAt: expiresAt(ttl)}
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.order.MoveToFront(element)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+	if m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		m.evict()
+	}
+}
+
+func (m *MemoryStore) DeletePrefix(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, element := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(element)
+		}
+	}
+}
+
+// evict sweeps out every expired value and, if the store is still over its
+// limit, the least recently used ones.
+func (m *MemoryStore) evict() {
+	now := time.Now()
+	for element := m.order.Back(); element != nil; {
+		previous := element.Prev()
+		if element.Value.(*memoryEntry).expired(now) {
+			m.remove(element)
+		}
+		element = previous
+	}
+
+	for m.order.Len() > m.maxEntries {
+		m.remove(m.order.Back())
+	}
+}
+
+func (m *MemoryStore) remove(element *list.Element) {
+	m.order.Remove(element)
+	delete(m.entries, element.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"log/slog"
+	"myspace/backend/internal/database"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteStore keeps entries in the cache_entries table so they survive
+// restarts. Failures are logged and treated as misses.
+type SQLiteStore struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewSQLiteStore(db *gorm.DB, logger *slog.Logger) *SQLiteStore {
+	return &SQLiteStore{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (s *SQLiteStore) Get(key string) ([]byte, bool) {
+	var entries []database.CacheEntry
+	if err := s.db.Where("key = ?", key).Limit(1).Find(&entries).Error; err != nil {
+		s.logger.Warn("cache read failed", "error", err)
+		return nil, false
+	}
+	if len(entries) == 0 {
+		return nil, false
+	}
+
+	entry := entries[0]
+	if entry.ExpiresAt != nil && time.Now().After(*entry.ExpiresAt) {
+		s.db.Where("key = ?", key).Delete(&database.CacheEntry{})
+		return nil, false
+	}
+
+	return entry.Value, true
+}
+
+func (s *SQLiteStore) Set(key string, value []byte, ttl time.Duration) {
+	entry := database.CacheEntry{Key: key, Value: value, ExpiresAt: expiresAt(ttl)}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at"}),
+	}).Create(&entry).Error
+	if err != nil {
+		s.logger.Warn("cache write failed", "error", err)
+	}
+}
+
+func (s *SQLiteStore) DeletePrefix(prefix string) {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+	if err := s.db.Where(`key LIKE ? ESCAPE '\'`, escaped+"%").Delete(&database.CacheEntry{}).Error; err != nil {
+		s.logger.Warn("cache invalidation failed", "error", err)
+	}
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"myspace/backend/internal/config"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/logging"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/syncer"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+// app is the wiring shared by commands that talk to the trackers and the
+// local database.
+type app struct {
+	cfg          *config.Config
+	logger       *slog.Logger
+	db           *gorm.DB
+	trackersRepo *repositories.TrackersRepository
+	tracksRepo   *repositories.TracksRepository
+	sync         *syncer.Engine
+}
+
+func bootstrap() (*app, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logger := logging.New(os.Stderr, cfg.Log.Level, cfg.Log.Format, cfg.Secrets.Values())
+	slog.SetDefault(logger)
+
+	db, err := database.Connect(cfg.Database.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	trackersRepo := repositories.NewTrackersRepository(cfg, logger)
+	tracksRepo := repositories.NewTracksRepository(db)
+
+	return &app{
+		cfg:          cfg,
+		logger:       logger,
+		db:           db,
+		trackersRepo: trackersRepo,
+		tracksRepo:   tracksRepo,
+		sync:         syncer.NewEngine(trackersRepo.Remote(), tracksRepo, cfg.Location, logger),
+	}, nil
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"myspace/backend/internal/interfaces"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/syncer"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// backfillCommand imports history into the local Track table:
+//
+//	main backfill -from 2022-01 -trackers clockify,mayven -delay 2s
+//
+// Progress is checkpointed per tracker, so running it again after an
+// interruption picks up where it stopped.
+func backfillCommand(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	fromFlag := fs.String("from", "", "first month to import (YYYY-MM)")
+	trackersFlag := fs.String("trackers", "", "comma-separated trackers to import (default all)")
+	delay := fs.Duration("delay", time.Second, "pause between provider calls")
+	restart := fs.Bool("restart", false, "ignore saved progress and start over")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fromFlag == "" {
+		return errors.New("-from is required")
+	}
+
+	a, err := bootstrap()
+	if err != nil {
+		return err
+	}
+
+	from, err := time.ParseInLocation("2006-01", *fromFlag, a.cfg.Location)
+	if err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+
+	trackers, err := selectTrackers(a.sync.Trackers(), *trackersFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	backfill := syncer.NewBackfill(a.sync, repositories.NewSettingsRepository(a.db), *delay)
+	for _, tracker := range trackers {
+		if err := backfill.Run(ctx, tracker, from, *restart); err != nil {
+			return fmt.Errorf("backfill of %s stopped: %w", tracker.GetSource(), err)
+		}
+	}
+
+	return nil
+}
+
+func selectTrackers(all []interfaces.TimeTracker, names string) ([]interfaces.TimeTracker, error) {
+	if names == "" {
+		return all, nil
+	}
+
+	bySource := make(map[string]interfaces.TimeTracker)
+	for _, tracker := range all {
+		bySource[tracker.GetSource()] = tracker
+	}
+
+	var selected []interfaces.TimeTracker
+	for _, name := range strings.Split(names, ",") {
+		tracker, ok := bySource[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("tracker %q is not configured", name)
+		}
+		selected = append(selected, tracker)
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"fmt"
+)
+
+type command func(args []string) error
+
+var registry = map[string]command{
+	"secrets":  secretsCommand,
+	"sync":     syncCommand,
+	"backfill": backfillCommand,
+}
+
+// Run executes the named subcommand, e.g. `main secrets encrypt`.
+func Run(name string, args []string) error {
+	cmd, ok := registry[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	return cmd(args)
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"myspace/backend/internal/secrets"
+	"os"
+)
+
+// secretsCommand manages the encrypted secrets file:
+//
+//	main secrets keygen
+//	main secrets encrypt -in secrets.json -out secrets.enc
+//
+// The key is read from SECRETS_KEY.
+func secretsCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: secrets keygen|encrypt|decrypt")
+	}
+
+	switch args[0] {
+	case "keygen":
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate key: %w", err)
+		}
+		fmt.Println(base64.StdEncoding.EncodeToString(key))
+		return nil
+	case "encrypt", "decrypt":
+		return secretsTransform(args[0], args[1:])
+	default:
+		return fmt.Errorf("unknown secrets command %q", args[0])
+	}
+}
+
+func secretsTransform(mode string, args []string) error {
+	fs := flag.NewFlagSet("secrets "+mode, flag.ContinueOnError)
+	in := fs.String("in", "", "input file")
+	out := fs.String("out", "", "output file (stdout when empty)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+
+	key, err := secrets.ParseKey(os.Getenv("SECRETS_KEY"))
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	var result []byte
+	if mode == "encrypt" {
+		var values map[string]string
+		if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("input must be a JSON object of strings: %w", err)
+		}
+		result, err = secrets.Encrypt(key, data)
+	} else {
+		result, err = secrets.Decrypt(key, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(append(result, '\n'))
+		return err
+	}
+	return os.WriteFile(*out, result, 0600)
+}
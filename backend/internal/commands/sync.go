@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"context"
+	"flag"
+)
+
+// syncCommand pulls the recent months from every tracker into the local
+// Track table once, e.g. `main sync -months 3`.
+func syncCommand(args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	months := fs.Int("months", 0, "number of months to sync, including the current one (default SYNC_MONTHS)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	a, err := bootstrap()
+	if err != nil {
+		return err
+	}
+
+	if *months <= 0 {
+		*months = a.cfg.Sync.Months
+	}
+
+	return a.sync.SyncRecent(context.Background(), *months)
+}
@@ -1,48 +1,224 @@
 package config
 
 import (
+	"fmt"
+	"myspace/backend/internal/secrets"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	Port string
-	
+
+	// Location is the default user timezone for day and month boundaries.
+	Location *time.Location
+
 	Database struct {
 		Path string
 	}
-	
+
 	Clockify struct {
-		Token       string
-		WorkspaceID string
-		UserID      string
+		Token         string
+		WorkspaceID   string
+		UserID        string
+		WebhookSecret string
 	}
-	
+
 	Everhour struct {
-		Token string
+		Token         string
+		WebhookSecret string
 	}
-	
+
 	Mayven struct {
 		Auth   string
 		ApiURL string
 	}
+
+	Log struct {
+		Level  string
+		Format string
+	}
+
+	Sync struct {
+		Interval      time.Duration
+		Schedule      string
+		Months        int
+		ReadFromStore bool
+	}
+
+	Live struct {
+		Interval time.Duration
+	}
+
+	// Job schedules; empty means the job only runs when triggered
+	Jobs struct {
+		WarmupSchedule string
+		ReportSchedule string
+		ReportsDir     string
+	}
+
+	// Invoices: Issuer is printed as the sender, TemplatesDir may hold an
+	// invoice.html and invoice.txt replacing the built-in templates
+	Invoices struct {
+		Issuer       string
+		TemplatesDir string
+		DueDays      int
+	}
+
+	Cache struct {
+		Driver     string
+		MaxEntries int
+		RunningTTL time.Duration
+		CurrentTTL time.Duration
+		PastTTL    time.Duration
+		ClosedTTL  time.Duration
+	}
+
+	Secrets *secrets.Resolver
 }
 
-func Load() *Config {
+func Load() (*Config, error) {
 	cfg := &Config{
 		Port: getEnv("PORT", "8080"),
 	}
-	
+
 	cfg.Database.Path = getEnv("DB_PATH", "./database.sqlite")
-	
-	cfg.Clockify.Token = getEnv("CLOCKIFY_TOKEN", "")
-	cfg.Clockify.WorkspaceID = getEnv("CLOCKIFY_WORKSPACE_ID", "")
-	cfg.Clockify.UserID = getEnv("CLOCKIFY_USER_ID", "")
-	
-	cfg.Everhour.Token = getEnv("EVERHOUR_TOKEN", "")
-	cfg.Mayven.Auth = getEnv("MAYVEN_AUTH", "")
-	cfg.Mayven.ApiURL = getEnv("MAYVEN_API_URL", "https://api.mayven.io")
-	
-	return cfg
+
+	loc, err := time.LoadLocation(getEnv("TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIMEZONE: %w", err)
+	}
+	cfg.Location = loc
+
+	cfg.Log.Level = getEnv("LOG_LEVEL", "info")
+	cfg.Log.Format = getEnv("LOG_FORMAT", "text")
+
+	if cfg.Sync.Interval, err = time.ParseDuration(getEnv("SYNC_INTERVAL", "0")); err != nil {
+		return nil, fmt.Errorf("invalid SYNC_INTERVAL: %w", err)
+	}
+	if cfg.Sync.Months, err = strconv.Atoi(getEnv("SYNC_MONTHS", "2")); err != nil {
+		return nil, fmt.Errorf("invalid SYNC_MONTHS: %w", err)
+	}
+	cfg.Sync.ReadFromStore = getEnv("READ_FROM_STORE", "false") == "true"
+	cfg.Sync.Schedule = getEnv("SYNC_SCHEDULE", "")
+	if cfg.Sync.Schedule == "" && cfg.Sync.Interval > 0 {
+		cfg.Sync.Schedule = "@every " + cfg.Sync.Interval.String()
+	}
+
+	cfg.Jobs.WarmupSchedule = getEnv("WARMUP_SCHEDULE", "")
+	cfg.Jobs.ReportSchedule = getEnv("REPORT_SCHEDULE", "")
+	cfg.Jobs.ReportsDir = getEnv("REPORTS_DIR", "./reports")
+
+	cfg.Invoices.Issuer = strings.ReplaceAll(getEnv("INVOICE_ISSUER", ""), `\n`, "\n")
+	cfg.Invoices.TemplatesDir = getEnv("INVOICE_TEMPLATES_DIR", "")
+	if cfg.Invoices.DueDays, err = strconv.Atoi(getEnv("INVOICE_DUE_DAYS", "14")); err != nil {
+		return nil, fmt.Errorf("invalid INVOICE_DUE_DAYS: %w", err)
+	}
+	if cfg.Invoices.DueDays < 0 {
+		return nil, fmt.Errorf("invalid INVOICE_DUE_DAYS: must not be negative")
+	}
+
+	cfg.Cache.Driver = getEnv("CACHE_DRIVER", "memory")
+	if cfg.Cache.MaxEntries, err = strconv.Atoi(getEnv("CACHE_MAX_ENTRIES", "1000")); err != nil {
+		return nil, fmt.Errorf("invalid CACHE_MAX_ENTRIES: %w", err)
+	}
+	durations := []struct {
+		target       *time.Duration
+		key          string
+		defaultValue string
+	}{
+		{&cfg.Cache.RunningTTL, "CACHE_RUNNING_TTL", "30s"},
+		{&cfg.Cache.CurrentTTL, "CACHE_CURRENT_TTL", "2m"},
+		{&cfg.Cache.PastTTL, "CACHE_PAST_TTL", "1h"},
+		{&cfg.Cache.ClosedTTL, "CACHE_CLOSED_TTL", "0"},
+		{&cfg.Live.Interval, "LIVE_INTERVAL", "15s"},
+	}
+	for _, duration := range durations {
+		if *duration.target, err = time.ParseDuration(getEnv(duration.key, duration.defaultValue)); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", duration.key, err)
+		}
+	}
+	if cfg.Live.Interval <= 0 {
+		return nil, fmt.Errorf("invalid LIVE_INTERVAL: must be positive")
+	}
+
+	resolver, err := newSecretsResolver()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Secrets = resolver
+
+	credentials := []struct {
+		target *string
+		key    string
+	}{
+		{&cfg.Clockify.Token, "CLOCKIFY_TOKEN"},
+		{&cfg.Everhour.Token, "EVERHOUR_TOKEN"},
+		{&cfg.Mayven.Auth, "MAYVEN_AUTH"},
+		{&cfg.Clockify.WebhookSecret, "CLOCKIFY_WEBHOOK_SECRET"},
+		{&cfg.Everhour.WebhookSecret, "EVERHOUR_WEBHOOK_SECRET"},
+	}
+
+	for _, credential := range credentials {
+		value, err := resolver.Secret(credential.key)
+		if err != nil {
+			return nil, err
+		}
+		*credential.target = value
+	}
+
+	settings := []struct {
+		target       *string
+		key          string
+		defaultValue string
+	}{
+		{&cfg.Clockify.WorkspaceID, "CLOCKIFY_WORKSPACE_ID", ""},
+		{&cfg.Clockify.UserID, "CLOCKIFY_USER_ID", ""},
+		{&cfg.Mayven.ApiURL, "MAYVEN_API_URL", "https://api.mayven.io"},
+	}
+
+	for _, setting := range settings {
+		value, err := resolver.Get(setting.key, setting.defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		*setting.target = value
+	}
+
+	return cfg, nil
+}
+
+// newSecretsResolver checks plain env vars first, then KEY_FILE variables,
+// then SECRETS_DIR and finally the optional encrypted SECRETS_FILE.
+func newSecretsResolver() (*secrets.Resolver, error) {
+	sources := []secrets.Source{
+		secrets.EnvSource{},
+		secrets.EnvFileSource{},
+		secrets.DirSource{Dir: getEnv("SECRETS_DIR", "/run/secrets")},
+	}
+
+	path := getEnv("SECRETS_FILE", "")
+	if path == "" {
+		return secrets.NewResolver(sources...), nil
+	}
+
+	encodedKey, err := secrets.NewResolver(sources...).Get("SECRETS_KEY", "")
+	if err != nil {
+		return nil, err
+	}
+	if encodedKey == "" {
+		return nil, fmt.Errorf("SECRETS_FILE is set but SECRETS_KEY is missing")
+	}
+
+	key, err := secrets.ParseKey(encodedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sources = append(sources, secrets.NewEncryptedFileSource(path, key))
+	return secrets.NewResolver(sources...), nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -50,4 +226,4 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
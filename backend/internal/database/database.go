@@ -22,5 +22,15 @@ func Migrate(db *gorm.DB) error {
 		&Project{},
 		&Track{},
 		&Setting{},
+		&CacheEntry{},
+		&Snapshot{},
+		&Holiday{},
+		&Leave{},
+		&OvertimeAdjustment{},
+		&ScheduleProfile{},
+		&BillingPeriod{},
+		&HourlyRate{},
+		&Invoice{},
+		&InvoiceLine{},
 	)
 }
\ No newline at end of file
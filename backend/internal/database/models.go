@@ -27,23 +27,30 @@ type Tracker struct {
 }
 
 type Project struct {
-	ID        uint    `json:"id" gorm:"primaryKey"`
-	TrackerID uint    `json:"tracker_id"`
-	Name      string  `json:"name"`
-	Token     string  `json:"token" gorm:"type:text"`
-	Tracker   Tracker `json:"tracker" gorm:"foreignKey:TrackerID"`
-	Tracks    []Track `json:"tracks" gorm:"foreignKey:ProjectID"`
+	ID         uint    `json:"id" gorm:"primaryKey"`
+	TrackerID  uint    `json:"tracker_id" gorm:"uniqueIndex:idx_projects_external"`
+	ExternalID string  `json:"external_id" gorm:"uniqueIndex:idx_projects_external"`
+	Name       string  `json:"name"`
+	Token      string  `json:"token" gorm:"type:text"`
+	Tracker    Tracker `json:"tracker" gorm:"foreignKey:TrackerID"`
+	Tracks     []Track `json:"tracks" gorm:"foreignKey:ProjectID"`
 }
 
+// Track is one synced time entry. Entries crossing a sync window boundary
+// are stored as one row per window, so Start is part of the key.
 type Track struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	TrackerID uint      `json:"tracker_id"`
-	ProjectID uint      `json:"project_id"`
-	Seconds   int       `json:"seconds"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Tracker   Tracker   `json:"tracker" gorm:"foreignKey:TrackerID"`
-	Project   Project   `json:"project" gorm:"foreignKey:ProjectID"`
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	TrackerID   uint       `json:"tracker_id" gorm:"uniqueIndex:idx_tracks_external"`
+	ProjectID   uint       `json:"project_id"`
+	ExternalID  string     `json:"external_id" gorm:"uniqueIndex:idx_tracks_external"`
+	Start       time.Time  `json:"start" gorm:"uniqueIndex:idx_tracks_external;index"`
+	End         *time.Time `json:"end"`
+	Seconds     int        `json:"seconds"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Tracker     Tracker    `json:"tracker" gorm:"foreignKey:TrackerID"`
+	Project     Project    `json:"project" gorm:"foreignKey:ProjectID"`
 }
 
 type Setting struct {
@@ -52,4 +59,139 @@ type Setting struct {
 	Value     string    `json:"value"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// Snapshot is the last successful month snapshot of a tracker, kept so the
+// API can fall back to it while the provider is unreachable.
+type Snapshot struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Source    string    `json:"source" gorm:"uniqueIndex:idx_snapshots_month"`
+	Month     string    `json:"month" gorm:"uniqueIndex:idx_snapshots_month"`
+	Location  string    `json:"location" gorm:"uniqueIndex:idx_snapshots_month"`
+	Data      []byte    `json:"data"`
+	FetchedAt time.Time `json:"fetched_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CacheEntry struct {
+	Key       string     `json:"key" gorm:"primaryKey"`
+	Value     []byte     `json:"value"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Holiday is an imported day off. Date is the calendar day, "2006-01-02".
+type Holiday struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Date      string    `json:"date" gorm:"uniqueIndex"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Leave is time off on one working day. Fraction is the part of the day
+// taken; one day can hold several entries, e.g. half vacation, half sick.
+type Leave struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Date      string    `json:"date" gorm:"index"`
+	Type      string    `json:"type"`
+	Fraction  float64   `json:"fraction"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OvertimeAdjustment corrects the overtime balance of a month, e.g. to carry
+// in a balance from before the ledger starts or book paid-out overtime.
+type OvertimeAdjustment struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Month     string    `json:"month" gorm:"index"`
+	Hours     float64   `json:"hours"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ScheduleProfile sets the hours worked per weekday from EffectiveFrom
+// ("2006-01-02") until the next profile takes effect.
+type ScheduleProfile struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	Name          string    `json:"name"`
+	EffectiveFrom string    `json:"effective_from" gorm:"uniqueIndex"`
+	Monday        float64   `json:"monday"`
+	Tuesday       float64   `json:"tuesday"`
+	Wednesday     float64   `json:"wednesday"`
+	Thursday      float64   `json:"thursday"`
+	Friday        float64   `json:"friday"`
+	Saturday      float64   `json:"saturday"`
+	Sunday        float64   `json:"sunday"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// BillingPeriod defines a client's billing periods: monthly from StartDay,
+// or cycles of Weeks weeks counted from Anchor ("2006-01-02").
+type BillingPeriod struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex"`
+	Kind      string    `json:"kind"`
+	StartDay  int       `json:"start_day"`
+	Weeks     int       `json:"weeks"`
+	Anchor    string    `json:"anchor"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HourlyRate is the rate billed for a project, or for all projects of a
+// source when ProjectID is empty, from EffectiveFrom ("2006-01-02") until
+// the next rate takes effect.
+type HourlyRate struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	Source        string    `json:"source" gorm:"uniqueIndex:idx_hourly_rates_effective"`
+	ProjectID     string    `json:"project_id" gorm:"uniqueIndex:idx_hourly_rates_effective"`
+	EffectiveFrom string    `json:"effective_from" gorm:"uniqueIndex:idx_hourly_rates_effective"`
+	Rate          float64   `json:"rate"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Invoice is an invoice with its figures copied in when it was created, so
+// later changes upstream don't alter it. Number is NULL until it is sent;
+// dates are "2006-01-02", empty when not set.
+type Invoice struct {
+	ID            uint          `json:"id" gorm:"primaryKey"`
+	Number        *string       `json:"number" gorm:"uniqueIndex"`
+	Status        string        `json:"status" gorm:"index"`
+	Client        string        `json:"client"`
+	ClientAddress string        `json:"client_address"`
+	Sources       string        `json:"sources"`
+	Projects      string        `json:"projects"`
+	PeriodFrom    string        `json:"period_from"`
+	PeriodTo      string        `json:"period_to"`
+	Currency      string        `json:"currency"`
+	Subtotal      float64       `json:"subtotal"`
+	TaxRate       float64       `json:"tax_rate"`
+	Tax           float64       `json:"tax"`
+	Total         float64       `json:"total"`
+	Note          string        `json:"note"`
+	IssuedOn      string        `json:"issued_on"`
+	DueOn         string        `json:"due_on"`
+	PaidOn        string        `json:"paid_on"`
+	Lines         []InvoiceLine `json:"lines" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// InvoiceLine is one project's hours at one rate on an invoice.
+type InvoiceLine struct {
+	ID          uint    `json:"id" gorm:"primaryKey"`
+	InvoiceID   uint    `json:"invoice_id" gorm:"index"`
+	Position    int     `json:"position"`
+	Source      string  `json:"source"`
+	ProjectID   string  `json:"project_id"`
+	Description string  `json:"description"`
+	Hours       float64 `json:"hours"`
+	Rate        float64 `json:"rate"`
+	Amount      float64 `json:"amount"`
+}
@@ -0,0 +1,168 @@
+package forecast
+
+import (
+	"math"
+	"myspace/backend/internal/workdays"
+	"time"
+)
+
+const (
+	// sampleDays is how many recent working days the daily average uses
+	sampleDays = 10
+	// confidence is the share of outcomes the low–high range covers, with
+	// z its normal quantile
+	confidence = 0.8
+	z          = 1.2816
+)
+
+// Point is one day of the burn-up: hours worked so far against the goal
+// line. Actual is nil for days after the reference day.
+type Point struct {
+	Date   string   `json:"date"`
+	Actual *float64 `json:"actual"`
+	Ideal  float64  `json:"ideal"`
+}
+
+// Forecast projects the period's total from the recent daily average.
+type Forecast struct {
+	Hours        float64 `json:"hours"`
+	Low          float64 `json:"low"`
+	High         float64 `json:"high"`
+	Confidence   float64 `json:"confidence"`
+	DailyAverage float64 `json:"daily_average"`
+	SampleDays   int     `json:"sample_days"`
+	Goal         float64 `json:"goal"`
+	ReachesGoal  bool    `json:"reaches_goal"`
+	GoalDate     *string `json:"goal_date"`
+}
+
+// Projection is the burn-up and forecast of a period.
+type Projection struct {
+	BurnUp   []Point  `json:"burn_up"`
+	Forecast Forecast `json:"forecast"`
+}
+
+// Build lays out the burn-up of the period [from, to) as of day, usually a
+// month, with dailyHours keyed by "2006-01-02" in from's timezone. The goal
+// line adds up each day's target less leave, like the expected hours of the
+// day view.
+//
+// The forecast adds the average of the last full working days before day to
+// each working day still to come. day itself counts as at least the average,
+// so a morning's few hours don't drag the forecast down. Days are assumed
+// independent, so the range widens with the square root of the days left.
+func Build(calendar *workdays.Calendar, from, to, day time.Time, dailyHours map[string]*float64) Projection {
+	goal := calendar.ExpectedHours(from, to)
+
+	hours := func(d time.Time) float64 {
+		if h := dailyHours[d.Format("2006-01-02")]; h != nil {
+			return *h
+		}
+		return 0
+	}
+	available := func(d time.Time) float64 {
+		if !calendar.IsWorkingDay(d) {
+			return 0
+		}
+		return 1 - calendar.LeaveFraction(d)
+	}
+
+	// Without any recent days to go by, each day is expected to meet its
+	// target
+	average, samples, deviation := recentAverage(calendar, from, day, hours)
+	rate := func(d time.Time) float64 {
+		if samples == 0 {
+			return calendar.Target(d)
+		}
+		return average
+	}
+
+	projection := Projection{BurnUp: []Point{}}
+	actual, ideal, projected := 0.0, 0.0, 0.0
+	remainingDays := 0.0
+	var goalDate *string
+
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		ideal += calendar.Target(d) * available(d)
+		point := Point{Date: date, Ideal: round2(ideal)}
+
+		switch {
+		case d.After(day):
+			projected += rate(d) * available(d)
+			remainingDays += available(d)
+		case sameDay(d, day):
+			actual += hours(d)
+			projected = actual
+			if rest := rate(d)*available(d) - hours(d); rest > 0 {
+				projected += rest
+				remainingDays += rest / rate(d)
+			}
+		default:
+			actual += hours(d)
+			projected = actual
+		}
+
+		if !d.After(day) {
+			value := round2(actual)
+			point.Actual = &value
+		}
+		if goalDate == nil && goal > 0 && projected >= goal-0.005 {
+			goalDate = &date
+		}
+
+		projection.BurnUp = append(projection.BurnUp, point)
+	}
+
+	spread := z * deviation * math.Sqrt(remainingDays)
+	projection.Forecast = Forecast{
+		Hours:        round2(projected),
+		Low:          round2(math.Max(projected-spread, actual)),
+		High:         round2(projected + spread),
+		Confidence:   confidence,
+		DailyAverage: round2(average),
+		SampleDays:   samples,
+		Goal:         round2(goal),
+		ReachesGoal:  goal <= 0 || goalDate != nil,
+		GoalDate:     goalDate,
+	}
+	return projection
+}
+
+// recentAverage averages the hours of the last full working days before
+// day in the period starting at from, returning how many there were and their standard
+// deviation.
+func recentAverage(calendar *workdays.Calendar, from, day time.Time, hours func(time.Time) float64) (float64, int, float64) {
+	var values []float64
+	for d := day.AddDate(0, 0, -1); !d.Before(from) && len(values) < sampleDays; d = d.AddDate(0, 0, -1) {
+		if calendar.IsWorkingDay(d) && calendar.LeaveFraction(d) == 0 {
+			values = append(values, hours(d))
+		}
+	}
+	if len(values) == 0 {
+		return 0, 0, 0
+	}
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, len(values), 0
+	}
+
+	squares := 0.0
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, len(values), math.Sqrt(squares / float64(len(values)-1))
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
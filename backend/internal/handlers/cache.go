@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"myspace/backend/internal/repositories"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CacheHandler struct {
+	trackersRepo *repositories.TrackersRepository
+}
+
+func NewCacheHandler(trackersRepo *repositories.TrackersRepository) *CacheHandler {
+	return &CacheHandler{
+		trackersRepo: trackersRepo,
+	}
+}
+
+// Invalidate drops cached tracker answers, for one source when :source is
+// given and for all of them otherwise.
+func (h *CacheHandler) Invalidate(c *gin.Context) {
+	source := c.Param("source")
+	if source != "" && !contains(h.trackersRepo.Sources(), source) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown source"})
+		return
+	}
+
+	h.trackersRepo.InvalidateCache(source)
+
+	c.JSON(http.StatusOK, gin.H{
+		"invalidated": true,
+		"source":      source,
+	})
+}
+
+func contains(items []string, item string) bool {
+	for _, candidate := range items {
+		if candidate == item {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"math"
+	"myspace/backend/internal/forecast"
 	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/workdays"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,11 +14,17 @@ import (
 
 type CalendarHandler struct {
 	trackersRepo *repositories.TrackersRepository
+	preferences  *repositories.PreferencesRepository
+	holidays     *repositories.HolidaysRepository
+	versions     *repositories.VersionsRepository
 }
 
-func NewCalendarHandler(trackersRepo *repositories.TrackersRepository) *CalendarHandler {
+func NewCalendarHandler(trackersRepo *repositories.TrackersRepository, preferences *repositories.PreferencesRepository, holidays *repositories.HolidaysRepository, versions *repositories.VersionsRepository) *CalendarHandler {
 	return &CalendarHandler{
 		trackersRepo: trackersRepo,
+		preferences:  preferences,
+		holidays:     holidays,
+		versions:     versions,
 	}
 }
 
@@ -35,50 +44,126 @@ func (h *CalendarHandler) Index(c *gin.Context) {
 		return
 	}
 	
-	date := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	date := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, location(c))
 	
-	dailyHours, err := h.trackersRepo.GetDailyHours(date)
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+	firstWeekday := prefs.FirstWeekday()
+	
+	snapshots, err := h.trackersRepo.MonthSnapshots(date)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get daily hours"})
 		return
 	}
+	dailyHours := snapshots.Intervals().GetDailyHours(date)
+	
+	nextMonth := date.AddDate(0, 1, 0)
+	calendar, err := h.holidays.Calendar(date, nextMonth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+		return
+	}
+	
+	days := h.getDays(date, dailyHours, firstWeekday, calendar)
 	
-	days := h.getDays(date, dailyHours)
+	now := time.Now().In(date.Location())
+	stale := snapshots.Stale(now)
 	
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"year":  year,
 		"month": month,
 		"days":  days,
-	})
+		"weeks": calendarWeeks(date, nextMonth, dailyHours, firstWeekday),
+		"week_start": prefs.WeekStart,
+		"monthly_goal": round2(calendar.MonthlyGoal(date)),
+		"stale": stale,
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for key, value := range periodProgress(calendar, date, nextMonth, today, snapshots.Hours(date, nextMonth)) {
+		response[key] = value
+	}
+	
+	projection := forecast.Build(calendar, date, nextMonth, today, dailyHours)
+	response["burn_up"] = projection.BurnUp
+	response["forecast"] = projection.Forecast
+	
+	modified, ok := lastModified(c, h.versions, snapshots.FetchedAt())
+	if !ok {
+		return
+	}
+	conditionalJSON(c, response, modified, monthFinished(date) && len(stale) == 0)
 }
 
-func (h *CalendarHandler) getDays(date time.Time, dailyHours map[string]*float64) []map[string]interface{} {
+func (h *CalendarHandler) getDays(date time.Time, dailyHours map[string]*float64, firstWeekday time.Weekday, calendar *workdays.Calendar) []map[string]interface{} {
 	som := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
 	eom := som.AddDate(0, 1, -1)
 	
 	var days []map[string]interface{}
 	
-	firstWeekday := int(som.Weekday())
-	if firstWeekday == 0 {
-		firstWeekday = 7
-	}
+	padding := (int(som.Weekday()) - int(firstWeekday) + 7) % 7
 	
-	for i := 1; i < firstWeekday; i++ {
+	for i := 0; i < padding; i++ {
 		days = append(days, map[string]interface{}{
-			"day":   nil,
-			"hours": nil,
+			"day":         nil,
+			"hours":       nil,
+			"working_day": false,
+			"holiday":     nil,
+			"leave":       nil,
+			"goal":        nil,
 		})
 	}
 	
 	for d := som; !d.After(eom); d = d.AddDate(0, 0, 1) {
 		dayStr := d.Format("2006-01-02")
 		hours := dailyHours[dayStr]
+		workingDay, holiday := dayOff(calendar, d)
 		
 		days = append(days, map[string]interface{}{
-			"day":   d.Day(),
-			"hours": hours,
+			"day":         d.Day(),
+			"hours":       hours,
+			"working_day": workingDay,
+			"holiday":     holiday,
+			"leave":       leaveList(calendar.Leave(d)),
+			"goal":        round2(calendar.Target(d)),
 		})
 	}
 	
 	return days
+}
+
+// calendarWeeks returns one total per row of a calendar layout of the days
+// in [from, to). Rows at the edges only count days in the range; each row
+// links to the ISO week of its last day, which is the week of its weekdays
+// whether rows start on Monday or Sunday.
+func calendarWeeks(from, to time.Time, dailyHours map[string]*float64, firstWeekday time.Weekday) []map[string]interface{} {
+	var weeks []map[string]interface{}
+	
+	for d := from; d.Before(to); {
+		total := 0.0
+		last := d
+		
+		for ; d.Before(to); d = d.AddDate(0, 0, 1) {
+			if hours := dailyHours[d.Format("2006-01-02")]; hours != nil {
+				total += *hours
+			}
+			last = d
+			if d.AddDate(0, 0, 1).Weekday() == firstWeekday {
+				d = d.AddDate(0, 0, 1)
+				break
+			}
+		}
+		isoYear, isoWeek := last.ISOWeek()
+		
+		weeks = append(weeks, map[string]interface{}{
+			"year":  isoYear,
+			"week":  isoWeek,
+			"hours": math.Round(total*100) / 100,
+			"link":  weekLink(isoYear, isoWeek),
+		})
+	}
+	
+	return weeks
 }
\ No newline at end of file
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"myspace/backend/internal/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pastMonthMaxAge is how long clients may keep views of months that are
+// over without asking again.
+const pastMonthMaxAge = 7 * 24 * time.Hour
+
+// conditionalJSON writes payload with an ETag of its content and, when
+// known, modified as Last-Modified. Clients that already have this version
+// get a 304. Views of finished months may be kept for pastMonthMaxAge;
+// everything else must be revalidated.
+func conditionalJSON(c *gin.Context, payload interface{}, modified time.Time, finished bool) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode response"})
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Vary", "X-Timezone")
+	if !modified.IsZero() {
+		c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if finished {
+		c.Header("Cache-Control", "private, max-age="+strconv.Itoa(int(pastMonthMaxAge.Seconds())))
+	} else {
+		c.Header("Cache-Control", "private, no-cache")
+	}
+
+	if notModified(c.Request, etag, modified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// notModified checks If-None-Match, or If-Modified-Since when no ETag was
+// sent.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !modified.IsZero() {
+		since, err := http.ParseTime(header)
+		if err != nil {
+			return false
+		}
+		return !modified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// lastModified is when a view's answer last changed: the later of fetched,
+// when its hours were fetched, and the last edit of the goals, leave,
+// schedules and rates it mixes in. It answers 500 and returns false when
+// the edits can't be read.
+func lastModified(c *gin.Context, versions *repositories.VersionsRepository, fetched time.Time) (time.Time, bool) {
+	modified, err := versions.Modified()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get data version"})
+		return time.Time{}, false
+	}
+	if fetched.After(modified) {
+		modified = fetched
+	}
+	return modified, true
+}
+
+// monthFinished reports whether the month containing date ended before
+// the current month in date's timezone.
+func monthFinished(date time.Time) bool {
+	now := time.Now().In(date.Location())
+	som := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, date.Location())
+	return date.Before(som)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"io"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/workdays"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxHolidayImport caps imported holiday lists; a year of a country's
+// iCalendar feed is a few kilobytes.
+const maxHolidayImport = 1 << 20
+
+type HolidaysHandler struct {
+	holidays    *repositories.HolidaysRepository
+	preferences *repositories.PreferencesRepository
+}
+
+func NewHolidaysHandler(holidays *repositories.HolidaysRepository, preferences *repositories.PreferencesRepository) *HolidaysHandler {
+	return &HolidaysHandler{
+		holidays:    holidays,
+		preferences: preferences,
+	}
+}
+
+// Index lists a year's holidays, the current one unless ?year= is given.
+func (h *HolidaysHandler) Index(c *gin.Context) {
+	year := time.Now().In(location(c)).Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		var err error
+		if year, err = strconv.Atoi(yearStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+	}
+
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+
+	holidays, err := h.holidays.Year(year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"year":      year,
+		"country":   prefs.HolidayCountry,
+		"work_week": prefs.WorkWeek,
+		"holidays":  holidayList(holidays),
+	})
+}
+
+func (h *HolidaysHandler) Countries(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"countries": workdays.Countries()})
+}
+
+// Import stores a JSON or iCalendar holiday list.
+func (h *HolidaysHandler) Import(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxHolidayImport))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+		return
+	}
+
+	holidays, err := workdays.ParseList(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.holidays.Import(holidays); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import holidays"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imported": len(holidays),
+		"holidays": holidayList(holidays),
+	})
+}
+
+// Delete removes an imported holiday.
+func (h *HolidaysHandler) Delete(c *gin.Context) {
+	date := c.Param("date")
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date"})
+		return
+	}
+
+	deleted, err := h.holidays.Delete(date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete holiday"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No imported holiday on that date"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true, "date": date})
+}
+
+func holidayList(holidays []workdays.Holiday) []gin.H {
+	list := make([]gin.H, 0, len(holidays))
+	for _, holiday := range holidays {
+		list = append(list, gin.H{
+			"date":   holiday.Date.Format("2006-01-02"),
+			"name":   holiday.Name,
+			"source": holiday.Source,
+		})
+	}
+	return list
+}
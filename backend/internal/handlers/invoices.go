@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"myspace/backend/internal/invoices"
+	"myspace/backend/internal/repositories"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvoicesHandler struct {
+	invoices     *repositories.InvoicesRepository
+	trackersRepo *repositories.TrackersRepository
+	rates        *repositories.RatesRepository
+	periods      *repositories.PeriodsRepository
+	preferences  *repositories.PreferencesRepository
+	renderer     *invoices.Renderer
+	dueDays      int
+}
+
+func NewInvoicesHandler(invoicesRepo *repositories.InvoicesRepository, trackersRepo *repositories.TrackersRepository, ratesRepo *repositories.RatesRepository, periodsRepo *repositories.PeriodsRepository, preferences *repositories.PreferencesRepository, renderer *invoices.Renderer, dueDays int) *InvoicesHandler {
+	return &InvoicesHandler{
+		invoices:     invoicesRepo,
+		trackersRepo: trackersRepo,
+		rates:        ratesRepo,
+		periods:      periodsRepo,
+		preferences:  preferences,
+		renderer:     renderer,
+		dueDays:      dueDays,
+	}
+}
+
+// invoiceRequest is the body of Create. The period is either the billing
+// period of period_id containing date, or from and to, both included. The
+// client is billed for every project of sources and for the projects named
+// as "source:project_id".
+type invoiceRequest struct {
+	Client        string   `json:"client"`
+	ClientAddress string   `json:"client_address"`
+	Sources       []string `json:"sources"`
+	Projects      []string `json:"projects"`
+	PeriodID      *uint    `json:"period_id"`
+	Date          string   `json:"date"`
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	TaxRate       *float64 `json:"tax_rate"`
+	Note          string   `json:"note"`
+}
+
+// Index lists the invoices, newest first, optionally only those in ?status.
+func (h *InvoicesHandler) Index(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && status != invoices.Draft && status != invoices.Sent && status != invoices.Paid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be draft, sent or paid"})
+		return
+	}
+
+	list, err := h.invoices.All(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get invoices"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(list))
+	for _, invoice := range list {
+		items = append(items, invoiceMap(invoice))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invoices": items})
+}
+
+func (h *InvoicesHandler) Show(c *gin.Context) {
+	invoice, ok := h.invoice(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, invoiceMap(invoice))
+}
+
+// Create drafts an invoice for the tracked hours of a period, priced at
+// the hourly rates in the preferred currency. The figures are stored with
+// it, so changing rates or entries later leaves it as it was.
+func (h *InvoicesHandler) Create(c *gin.Context) {
+	var request invoiceRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if strings.TrimSpace(request.Client) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "client is required"})
+		return
+	}
+	taxRate := 0.0
+	if request.TaxRate != nil {
+		taxRate = *request.TaxRate
+	}
+	if taxRate < 0 || taxRate > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tax_rate must be between 0 and 100"})
+		return
+	}
+
+	fetch, ok := h.scope(c, &request)
+	if !ok {
+		return
+	}
+
+	from, to, ok := h.period(c, &request)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+
+	// A tracker that can't be reached would leave its hours off the bill,
+	// so nothing is drafted until every one billed answers
+	entries, projects, err := h.trackersRepo.BillableTimes(from, to, fetch)
+	if errors.Is(err, repositories.ErrTrackersUnavailable) {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get intervals"})
+		return
+	}
+	entries = entries.Clip(from, to)
+
+	rateTable, err := h.rates.Table()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rates"})
+		return
+	}
+	last := to.AddDate(0, 0, -1)
+	earnings := rateTable.Price(entries, projects, from.Location(), last)
+
+	titles := make(map[string]string)
+	for _, project := range projects {
+		titles[project.Source+":"+project.ProjectID] = project.ProjectTitle
+	}
+
+	invoice, err := invoices.Build(invoices.Invoice{
+		Client:        strings.TrimSpace(request.Client),
+		ClientAddress: request.ClientAddress,
+		Sources:       request.Sources,
+		Projects:      request.Projects,
+		From:          time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC),
+		To:            time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC),
+		Currency:      prefs.Currency,
+		TaxRate:       taxRate,
+		Note:          request.Note,
+	}, earnings, titles)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(invoice.Lines) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No hours to bill in the period"})
+		return
+	}
+
+	if err := h.invoices.Create(&invoice); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save invoice"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoiceMap(invoice))
+}
+
+// Status moves an invoice on: sending a draft numbers it, paying a sent
+// one records the day.
+func (h *InvoicesHandler) Status(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	var request struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if request.Status != invoices.Sent && request.Status != invoices.Paid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be sent or paid"})
+		return
+	}
+
+	now := time.Now().In(location(c))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	invoice, found, err := h.invoices.Move(uint(id), request.Status, today, h.dueDays)
+	if errors.Is(err, repositories.ErrInvoiceStatus) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update invoice"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoiceMap(invoice))
+}
+
+// Delete removes a draft; sent invoices keep their number for good.
+func (h *InvoicesHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	deleted, err := h.invoices.Delete(uint(id))
+	if errors.Is(err, repositories.ErrInvoiceIssued) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete invoice"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true, "id": id})
+}
+
+// HTML renders the invoice as a page.
+func (h *InvoicesHandler) HTML(c *gin.Context) {
+	invoice, ok := h.invoice(c)
+	if !ok {
+		return
+	}
+
+	var page bytes.Buffer
+	if err := h.renderer.HTML(&page, invoice); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render invoice"})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// PDF renders the invoice as a PDF document.
+func (h *InvoicesHandler) PDF(c *gin.Context) {
+	invoice, ok := h.invoice(c)
+	if !ok {
+		return
+	}
+
+	var document bytes.Buffer
+	if err := h.renderer.PDF(&document, invoice); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render invoice"})
+		return
+	}
+
+	name := invoice.Number
+	if name == "" {
+		name = fmt.Sprintf("draft-%d", invoice.ID)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="invoice-%s.pdf"`, name))
+	c.Data(http.StatusOK, "application/pdf", document.Bytes())
+}
+
+// invoice loads the invoice named by :id, answering 400 or 404 and
+// returning false when there is none.
+func (h *InvoicesHandler) invoice(c *gin.Context) (invoices.Invoice, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return invoices.Invoice{}, false
+	}
+
+	invoice, found, err := h.invoices.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get invoice"})
+		return invoices.Invoice{}, false
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		return invoices.Invoice{}, false
+	}
+	return invoice, true
+}
+
+// period resolves the range [from, to) request bills, answering 400 or 404
+// and returning false when it can't.
+func (h *InvoicesHandler) period(c *gin.Context, request *invoiceRequest) (time.Time, time.Time, bool) {
+	loc := location(c)
+
+	if request.PeriodID != nil {
+		date, err := time.ParseInLocation("2006-01-02", request.Date, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be a date, YYYY-MM-DD"})
+			return time.Time{}, time.Time{}, false
+		}
+
+		definition, found, err := h.periods.Get(*request.PeriodID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get period"})
+			return time.Time{}, time.Time{}, false
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Period not found"})
+			return time.Time{}, time.Time{}, false
+		}
+
+		period := definition.Containing(date)
+		return period.From, period.To, true
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", request.From, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period_id and date, or from and to are required"})
+		return time.Time{}, time.Time{}, false
+	}
+	last, err := time.ParseInLocation("2006-01-02", request.To, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date, YYYY-MM-DD"})
+		return time.Time{}, time.Time{}, false
+	}
+	if last.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, last.AddDate(0, 0, 1), true
+}
+
+// scope checks the sources and projects request bills, answering 400 and
+// returning false when they are missing or name an unknown source. It
+// returns the sources to fetch.
+func (h *InvoicesHandler) scope(c *gin.Context, request *invoiceRequest) ([]string, bool) {
+	if len(request.Sources) == 0 && len(request.Projects) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sources or projects is required"})
+		return nil, false
+	}
+
+	known := make(map[string]bool)
+	for _, source := range h.trackersRepo.Sources() {
+		known[source] = true
+	}
+
+	var fetch []string
+	selected := make(map[string]bool)
+	add := func(source string) bool {
+		if !known[source] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown source " + strconv.Quote(source)})
+			return false
+		}
+		if !selected[source] {
+			selected[source] = true
+			fetch = append(fetch, source)
+		}
+		return true
+	}
+
+	for _, source := range request.Sources {
+		if !add(source) {
+			return nil, false
+		}
+	}
+	for _, project := range request.Projects {
+		source, projectID, found := strings.Cut(project, ":")
+		if !found || projectID == "" || strings.Contains(projectID, ",") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "projects must be given as source:project_id"})
+			return nil, false
+		}
+		if !add(source) {
+			return nil, false
+		}
+	}
+
+	if request.Sources == nil {
+		request.Sources = []string{}
+	}
+	if request.Projects == nil {
+		request.Projects = []string{}
+	}
+	return fetch, true
+}
+
+func invoiceMap(invoice invoices.Invoice) gin.H {
+	lines := make([]gin.H, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		var projectID interface{}
+		if line.ProjectID != "" {
+			projectID = line.ProjectID
+		}
+		lines = append(lines, gin.H{
+			"source":      line.Source,
+			"project_id":  projectID,
+			"description": line.Description,
+			"hours":       line.Hours,
+			"rate":        line.Rate,
+			"amount":      line.Amount,
+		})
+	}
+
+	day := func(t time.Time) interface{} {
+		if t.IsZero() {
+			return nil
+		}
+		return t.Format("2006-01-02")
+	}
+	var number interface{}
+	if invoice.Number != "" {
+		number = invoice.Number
+	}
+
+	return gin.H{
+		"id":             invoice.ID,
+		"number":         number,
+		"status":         invoice.Status,
+		"client":         invoice.Client,
+		"client_address": invoice.ClientAddress,
+		"sources":        invoice.Sources,
+		"projects":       invoice.Projects,
+		"from":           day(invoice.From),
+		"to":             day(invoice.To),
+		"currency":       invoice.Currency,
+		"lines":          lines,
+		"subtotal":       invoice.Subtotal,
+		"tax_rate":       invoice.TaxRate,
+		"tax":            invoice.Tax,
+		"total":          invoice.Total,
+		"note":           invoice.Note,
+		"issued_on":      day(invoice.IssuedOn),
+		"due_on":         day(invoice.DueOn),
+		"paid_on":        day(invoice.PaidOn),
+		"created_at":     invoice.CreatedAt,
+		"html_link":      fmt.Sprintf("/invoices/%d/html", invoice.ID),
+		"pdf_link":       fmt.Sprintf("/invoices/%d/pdf", invoice.ID),
+	}
+}
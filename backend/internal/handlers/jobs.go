@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"myspace/backend/internal/scheduler"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobsHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewJobsHandler(scheduler *scheduler.Scheduler) *JobsHandler {
+	return &JobsHandler{
+		scheduler: scheduler,
+	}
+}
+
+func (h *JobsHandler) Index(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"jobs": h.scheduler.Jobs(),
+	})
+}
+
+// Run starts a job in the background; its outcome shows up in Index.
+func (h *JobsHandler) Run(c *gin.Context) {
+	name := c.Param("name")
+
+	err := h.scheduler.Trigger(name)
+	switch {
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown job"})
+		return
+	case errors.Is(err, scheduler.ErrRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": "Job is already running"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start job"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"job":     name,
+		"started": true,
+	})
+}
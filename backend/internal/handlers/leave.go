@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/workdays"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveHandler struct {
+	leave       *repositories.LeaveRepository
+	holidays    *repositories.HolidaysRepository
+	preferences *repositories.PreferencesRepository
+}
+
+func NewLeaveHandler(leave *repositories.LeaveRepository, holidays *repositories.HolidaysRepository, preferences *repositories.PreferencesRepository) *LeaveHandler {
+	return &LeaveHandler{
+		leave:       leave,
+		holidays:    holidays,
+		preferences: preferences,
+	}
+}
+
+// leaveRequest is the body of Create and Update. Partial days are given as
+// a fraction of the day or as hours of that day's goal.
+type leaveRequest struct {
+	Date     string   `json:"date"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Type     *string  `json:"type"`
+	Fraction *float64 `json:"fraction"`
+	Hours    *float64 `json:"hours"`
+	Note     *string  `json:"note"`
+}
+
+// Index lists a year's leave with the yearly balances, the current year
+// unless ?year= is given.
+func (h *LeaveHandler) Index(c *gin.Context) {
+	today := time.Now().In(location(c))
+	year := today.Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		var err error
+		if year, err = strconv.Atoi(yearStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+	}
+
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	calendar, err := h.holidays.Calendar(from, from.AddDate(1, 0, 0))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get leave"})
+		return
+	}
+
+	leave, err := h.leave.Year(year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get leave"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"year":     year,
+		"leave":    leaveList(leave),
+		"balances": leaveBalances(calendar, leave, today.Format("2006-01-02"), prefs.VacationDays),
+	})
+}
+
+// leaveBalances sums the leave of each type, split into days already taken
+// and days planned after today. Leave on days that have since become days
+// off doesn't count.
+func leaveBalances(calendar *workdays.Calendar, leave []workdays.Leave, today string, allowance float64) []gin.H {
+	taken := make(map[string]float64)
+	planned := make(map[string]float64)
+	for _, entry := range leave {
+		if !calendar.IsWorkingDay(entry.Date) {
+			continue
+		}
+		if entry.Date.Format("2006-01-02") > today {
+			planned[entry.Type] += entry.Fraction
+		} else {
+			taken[entry.Type] += entry.Fraction
+		}
+	}
+
+	balances := make([]gin.H, 0, len(workdays.LeaveTypes))
+	for _, leaveType := range workdays.LeaveTypes {
+		balance := gin.H{
+			"type":    leaveType,
+			"taken":   round2(taken[leaveType]),
+			"planned": round2(planned[leaveType]),
+			"total":   round2(taken[leaveType] + planned[leaveType]),
+		}
+		if leaveType == workdays.LeaveVacation && allowance > 0 {
+			balance["allowance"] = allowance
+			balance["remaining"] = round2(allowance - taken[leaveType] - planned[leaveType])
+		}
+		balances = append(balances, balance)
+	}
+	return balances
+}
+
+// Create adds leave on one date, or on every working day from from to to
+// inclusive.
+func (h *LeaveHandler) Create(c *gin.Context) {
+	var request leaveRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+
+	if request.Date != "" {
+		request.From, request.To = request.Date, request.Date
+	}
+	if request.To == "" {
+		request.To = request.From
+	}
+	from, err := time.Parse("2006-01-02", request.From)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+		return
+	}
+	to, err := time.Parse("2006-01-02", request.To)
+	if err != nil || to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+		return
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Leave can't span more than a year"})
+		return
+	}
+
+	entry := workdays.Leave{Type: workdays.LeaveVacation, Fraction: 1}
+	if !h.apply(c, &request, &entry) {
+		return
+	}
+
+	calendar, err := h.holidays.Calendar(from, to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+		return
+	}
+
+	var entries []workdays.Leave
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !calendar.IsWorkingDay(d) {
+			continue
+		}
+		entry.Date = d
+		if !h.applyHours(c, &request, calendar, &entry) {
+			return
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No working days in range"})
+		return
+	}
+
+	created, err := h.leave.Create(entries)
+	if errors.Is(err, repositories.ErrLeaveExceedsDay) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create leave"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"leave": leaveList(created)})
+}
+
+// Update changes the type, size or note of one entry and keeps the rest.
+func (h *LeaveHandler) Update(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+
+	entry, found, err := h.leave.Get(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get leave"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave not found"})
+		return
+	}
+
+	var request leaveRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if !h.apply(c, &request, &entry) {
+		return
+	}
+	if request.Hours != nil {
+		calendar, err := h.holidays.Calendar(entry.Date, entry.Date.AddDate(0, 0, 1))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+			return
+		}
+		if !h.applyHours(c, &request, calendar, &entry) {
+			return
+		}
+	}
+
+	entry, found, err = h.leave.Update(id, entry.Type, entry.Fraction, entry.Note)
+	if errors.Is(err, repositories.ErrLeaveExceedsDay) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update leave"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaveList([]workdays.Leave{entry})[0])
+}
+
+func (h *LeaveHandler) Delete(c *gin.Context) {
+	id, ok := h.id(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.leave.Delete(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete leave"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true, "id": id})
+}
+
+// apply copies the fields present in request onto entry, answering 400 and
+// returning false when one is invalid. Hours depend on the day and are
+// applied by applyHours.
+func (h *LeaveHandler) apply(c *gin.Context, request *leaveRequest, entry *workdays.Leave) bool {
+	if request.Type != nil {
+		if !workdays.IsLeaveType(*request.Type) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "type must be vacation, sick or unpaid"})
+			return false
+		}
+		entry.Type = *request.Type
+	}
+
+	if request.Fraction != nil && request.Hours == nil {
+		if !validFraction(c, *request.Fraction) {
+			return false
+		}
+		entry.Fraction = *request.Fraction
+	}
+
+	if request.Note != nil {
+		entry.Note = *request.Note
+	}
+	return true
+}
+
+// applyHours turns the hours in request, if any, into the part of entry's
+// day they take, by that day's target, the same hours its expected hours
+// are made of. It answers 400 and returns false when the day has no target
+// or the hours don't fit.
+func (h *LeaveHandler) applyHours(c *gin.Context, request *leaveRequest, calendar *workdays.Calendar, entry *workdays.Leave) bool {
+	if request.Hours == nil {
+		return true
+	}
+
+	goal := calendar.Target(entry.Date)
+	if goal <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No daily goal on " + entry.Date.Format("2006-01-02") + " to take hours from; give a fraction instead"})
+		return false
+	}
+
+	fraction := *request.Hours / goal
+	if !validFraction(c, fraction) {
+		return false
+	}
+	entry.Fraction = fraction
+	return true
+}
+
+func validFraction(c *gin.Context, fraction float64) bool {
+	if fraction <= 0 || fraction > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Leave must be more than nothing and at most a full day"})
+		return false
+	}
+	return true
+}
+
+func (h *LeaveHandler) id(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return 0, false
+	}
+	return uint(id), true
+}
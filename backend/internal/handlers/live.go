@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"io"
+	"myspace/backend/internal/live"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LiveHandler struct {
+	poller *live.Poller
+}
+
+func NewLiveHandler(poller *live.Poller) *LiveHandler {
+	return &LiveHandler{
+		poller: poller,
+	}
+}
+
+// Stream pushes running timers, totals and goal events as server-sent
+// events until the client goes away.
+func (h *LiveHandler) Stream(c *gin.Context) {
+	events, unsubscribe := h.poller.Subscribe(location(c))
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-events:
+			c.SSEvent(event.Name, event.Data)
+			return true
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/repositories"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OvertimeHandler struct {
+	overtime *repositories.OvertimeRepository
+}
+
+func NewOvertimeHandler(overtime *repositories.OvertimeRepository) *OvertimeHandler {
+	return &OvertimeHandler{
+		overtime: overtime,
+	}
+}
+
+// Index returns the ledger up to today, or up to ?until=YYYY-MM-DD, with
+// the adjustments it includes.
+func (h *OvertimeHandler) Index(c *gin.Context) {
+	loc := location(c)
+	now := time.Now().In(loc)
+	until := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if untilStr := c.Query("until"); untilStr != "" {
+		var err error
+		if until, err = time.ParseInLocation("2006-01-02", untilStr, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid until date"})
+			return
+		}
+	}
+
+	ledger, err := h.overtime.Ledger(until)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get overtime"})
+		return
+	}
+
+	adjustments, err := h.overtime.Adjustments(ledger.Start, until.Format("2006-01"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get overtime adjustments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"start":       ledger.Start,
+		"until":       ledger.Until,
+		"months":      ledger.Months,
+		"balance":     ledger.Balance,
+		"adjustments": adjustmentList(adjustments),
+	})
+}
+
+func (h *OvertimeHandler) AddAdjustment(c *gin.Context) {
+	var request struct {
+		Month string   `json:"month"`
+		Hours *float64 `json:"hours"`
+		Note  string   `json:"note"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if _, err := time.Parse("2006-01", request.Month); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "month must be YYYY-MM"})
+		return
+	}
+	if request.Hours == nil || *request.Hours == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hours must be a non-zero number"})
+		return
+	}
+
+	adjustment := database.OvertimeAdjustment{
+		Month: request.Month,
+		Hours: *request.Hours,
+		Note:  request.Note,
+	}
+	if err := h.overtime.AddAdjustment(&adjustment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add overtime adjustment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, adjustmentList([]database.OvertimeAdjustment{adjustment})[0])
+}
+
+func (h *OvertimeHandler) DeleteAdjustment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	deleted, err := h.overtime.DeleteAdjustment(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete overtime adjustment"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Adjustment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true, "id": id})
+}
+
+func adjustmentList(adjustments []database.OvertimeAdjustment) []gin.H {
+	list := make([]gin.H, 0, len(adjustments))
+	for _, adjustment := range adjustments {
+		list = append(list, gin.H{
+			"id":    adjustment.ID,
+			"month": adjustment.Month,
+			"hours": adjustment.Hours,
+			"note":  adjustment.Note,
+		})
+	}
+	return list
+}
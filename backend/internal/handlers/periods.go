@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"myspace/backend/internal/forecast"
+	"myspace/backend/internal/periods"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/types"
+	"myspace/backend/internal/workdays"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PeriodsHandler struct {
+	periods      *repositories.PeriodsRepository
+	trackersRepo *repositories.TrackersRepository
+	preferences  *repositories.PreferencesRepository
+	holidays     *repositories.HolidaysRepository
+	versions     *repositories.VersionsRepository
+}
+
+func NewPeriodsHandler(periodsRepo *repositories.PeriodsRepository, trackersRepo *repositories.TrackersRepository, preferences *repositories.PreferencesRepository, holidays *repositories.HolidaysRepository, versions *repositories.VersionsRepository) *PeriodsHandler {
+	return &PeriodsHandler{
+		periods:      periodsRepo,
+		trackersRepo: trackersRepo,
+		preferences:  preferences,
+		holidays:     holidays,
+		versions:     versions,
+	}
+}
+
+// periodRequest is the body of Create and Update.
+type periodRequest struct {
+	Name     *string `json:"name"`
+	Kind     *string `json:"kind"`
+	StartDay *int    `json:"start_day"`
+	Weeks    *int    `json:"weeks"`
+	Anchor   *string `json:"anchor"`
+}
+
+// Index lists the definitions with the period each is in today.
+func (h *PeriodsHandler) Index(c *gin.Context) {
+	definitions, err := h.periods.All()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get periods"})
+		return
+	}
+
+	now := time.Now().In(location(c))
+	list := make([]gin.H, 0, len(definitions))
+	for _, definition := range definitions {
+		item := definitionMap(definition)
+		item["current"] = periodMap(definition, definition.Containing(now))
+		list = append(list, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"periods": list})
+}
+
+func (h *PeriodsHandler) Create(c *gin.Context) {
+	var request periodRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if request.Name == nil || *request.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	definition := periods.Definition{Kind: periods.Monthly, StartDay: 1}
+	if !applyPeriod(c, &request, &definition) {
+		return
+	}
+	h.save(c, &definition, http.StatusCreated)
+}
+
+// Update changes the fields present in the body.
+func (h *PeriodsHandler) Update(c *gin.Context) {
+	definition, ok := h.definition(c)
+	if !ok {
+		return
+	}
+
+	var request periodRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if request.Name != nil && *request.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+		return
+	}
+	if !applyPeriod(c, &request, &definition) {
+		return
+	}
+	h.save(c, &definition, http.StatusOK)
+}
+
+func (h *PeriodsHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	deleted, err := h.periods.Delete(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete period"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Period not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true, "id": id})
+}
+
+// Redirect goes to the projects of the period today is in.
+func (h *PeriodsHandler) Redirect(c *gin.Context) {
+	definition, ok := h.definition(c)
+	if !ok {
+		return
+	}
+
+	period := definition.Containing(time.Now().In(location(c)))
+	c.Redirect(http.StatusFound, redirectURL(c, periodLink(definition, period)+"/projects"))
+}
+
+// Projects is the projects view of the period containing :date.
+func (h *PeriodsHandler) Projects(c *gin.Context) {
+	h.view(c, "projects", func(view *periodView) gin.H {
+		projectTimes := view.intervals.GroupByProject()
+		return gin.H{
+			"projects":    projectTimes.ToArray(),
+			"total_hours": projectTimes.GetHours(),
+		}
+	})
+}
+
+// Calendar is the calendar view of the period containing :date, laid out
+// in rows like the month calendar. Days carry their date since a period
+// may span two months.
+func (h *PeriodsHandler) Calendar(c *gin.Context) {
+	h.view(c, "calendar", func(view *periodView) gin.H {
+		from, to := view.period.From, view.period.To
+
+		var days []map[string]interface{}
+		padding := (int(from.Weekday()) - int(view.firstWeekday) + 7) % 7
+		for i := 0; i < padding; i++ {
+			days = append(days, map[string]interface{}{
+				"date":        nil,
+				"day":         nil,
+				"hours":       nil,
+				"working_day": false,
+				"holiday":     nil,
+				"leave":       nil,
+				"goal":        nil,
+			})
+		}
+		for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+			workingDay, holiday := dayOff(view.calendar, d)
+			days = append(days, map[string]interface{}{
+				"date":        d.Format("2006-01-02"),
+				"day":         d.Day(),
+				"hours":       view.dailyHours[d.Format("2006-01-02")],
+				"working_day": workingDay,
+				"holiday":     holiday,
+				"leave":       leaveList(view.calendar.Leave(d)),
+				"goal":        round2(view.calendar.Target(d)),
+			})
+		}
+
+		return gin.H{
+			"days":        days,
+			"weeks":       calendarWeeks(from, to, view.dailyHours, view.firstWeekday),
+			"week_start":  view.weekStart,
+			"total_hours": view.hours,
+		}
+	})
+}
+
+// periodView is what both period views are built from.
+type periodView struct {
+	period       periods.Period
+	intervals    types.ProjectTimeList
+	dailyHours   map[string]*float64
+	hours        float64
+	calendar     *workdays.Calendar
+	firstWeekday time.Weekday
+	weekStart    string
+}
+
+// view answers with the fields every period view shares, the goal, the
+// working-day progress, burn-up and forecast, plus those fields returns.
+// name is the view's last path segment, for the navigation links.
+func (h *PeriodsHandler) view(c *gin.Context, name string, fields func(*periodView) gin.H) {
+	definition, ok := h.definition(c)
+	if !ok {
+		return
+	}
+
+	loc := location(c)
+	date, err := time.ParseInLocation("2006-01-02", c.Param("date"), loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date"})
+		return
+	}
+	period := definition.Containing(date)
+
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+
+	// The snapshots of every month the period touches, so the period shares
+	// the month views' cache and falls back like them when a provider is down
+	snapshots, err := h.trackersRepo.RangeSnapshots(period.From, period.To)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get intervals"})
+		return
+	}
+	intervals := snapshots.Intervals().Clip(period.From, period.To)
+	stale := snapshots.Stale(time.Now())
+
+	// Days without a schedule profile are capped by the goal of their own
+	// month, so the calendar covers every month the period touches
+	calendarFrom := time.Date(period.From.Year(), period.From.Month(), 1, 0, 0, 0, 0, loc)
+	last := period.Last()
+	calendarTo := time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, 1, 0)
+	calendar, err := h.holidays.Calendar(calendarFrom, calendarTo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+		return
+	}
+
+	view := &periodView{
+		period:       period,
+		intervals:    intervals,
+		dailyHours:   intervals.GetDailyHoursBetween(period.From, period.To),
+		hours:        intervals.GetHours(),
+		calendar:     calendar,
+		firstWeekday: prefs.FirstWeekday(),
+		weekStart:    prefs.WeekStart,
+	}
+
+	prev, next := definition.Previous(period), definition.Next(period)
+	nav := gin.H{
+		"title":     definition.Name,
+		"range":     period.From.Format("Jan 2") + " – " + last.Format("Jan 2"),
+		"prev_link": periodLink(definition, prev) + "/" + name,
+		"next_link": periodLink(definition, next) + "/" + name,
+	}
+
+	response := gin.H{
+		"period":      definitionMap(definition),
+		"from":        period.From.Format("2006-01-02"),
+		"last":        last.Format("2006-01-02"),
+		"period_goal": round2(calendar.Goal(period.From, period.To)),
+		"nav":         nav,
+		"stale":       stale,
+	}
+	for key, value := range fields(view) {
+		response[key] = value
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	for key, value := range periodProgress(calendar, period.From, period.To, today, view.hours) {
+		response[key] = value
+	}
+
+	projection := forecast.Build(calendar, period.From, period.To, today, view.dailyHours)
+	response["burn_up"] = projection.BurnUp
+	response["forecast"] = projection.Forecast
+
+	modified, ok := lastModified(c, h.versions, snapshots.FetchedAt())
+	if !ok {
+		return
+	}
+	conditionalJSON(c, response, modified, monthFinished(last) && len(stale) == 0)
+}
+
+// definition loads the definition named by :id, answering 400 or 404 and
+// returning false when there is none.
+func (h *PeriodsHandler) definition(c *gin.Context) (periods.Definition, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return periods.Definition{}, false
+	}
+
+	definition, found, err := h.periods.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get period"})
+		return periods.Definition{}, false
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Period not found"})
+		return periods.Definition{}, false
+	}
+	return definition, true
+}
+
+func (h *PeriodsHandler) save(c *gin.Context, definition *periods.Definition, status int) {
+	err := h.periods.Save(definition)
+	if errors.Is(err, repositories.ErrPeriodExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrPeriodNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Period not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save period"})
+		return
+	}
+
+	c.JSON(status, definitionMap(*definition))
+}
+
+// applyPeriod copies the fields present in request onto definition,
+// answering 400 and returning false when the result is invalid.
+func applyPeriod(c *gin.Context, request *periodRequest, definition *periods.Definition) bool {
+	if request.Name != nil {
+		definition.Name = *request.Name
+	}
+	if request.Kind != nil {
+		definition.Kind = *request.Kind
+	}
+	if request.StartDay != nil {
+		definition.StartDay = *request.StartDay
+	}
+	if request.Weeks != nil {
+		definition.Weeks = *request.Weeks
+	}
+	if request.Anchor != nil {
+		anchor, err := time.Parse("2006-01-02", *request.Anchor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "anchor must be a date, YYYY-MM-DD"})
+			return false
+		}
+		definition.Anchor = anchor
+	}
+
+	if err := definition.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func definitionMap(definition periods.Definition) gin.H {
+	item := gin.H{
+		"id":   definition.ID,
+		"name": definition.Name,
+		"kind": definition.Kind,
+	}
+	if definition.Kind == periods.Cycle {
+		item["weeks"] = definition.Weeks
+		item["anchor"] = definition.Anchor.Format("2006-01-02")
+	} else {
+		item["start_day"] = definition.StartDay
+	}
+	return item
+}
+
+func periodMap(definition periods.Definition, period periods.Period) gin.H {
+	return gin.H{
+		"from": period.From.Format("2006-01-02"),
+		"last": period.Last().Format("2006-01-02"),
+		"link": periodLink(definition, period) + "/projects",
+	}
+}
+
+// periodLink is the path of a period, by the day it starts on.
+func periodLink(definition periods.Definition, period periods.Period) string {
+	return "/periods/" + strconv.FormatUint(uint64(definition.ID), 10) + "/" + period.From.Format("2006-01-02")
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"myspace/backend/internal/forecast"
 	"myspace/backend/internal/repositories"
 	"net/http"
 	"strconv"
@@ -11,18 +12,26 @@ import (
 
 type ProjectsHandler struct {
 	trackersRepo *repositories.TrackersRepository
+	preferences  *repositories.PreferencesRepository
+	holidays     *repositories.HolidaysRepository
+	rates        *repositories.RatesRepository
+	versions     *repositories.VersionsRepository
 }
 
-func NewProjectsHandler(trackersRepo *repositories.TrackersRepository) *ProjectsHandler {
+func NewProjectsHandler(trackersRepo *repositories.TrackersRepository, preferences *repositories.PreferencesRepository, holidays *repositories.HolidaysRepository, ratesRepo *repositories.RatesRepository, versions *repositories.VersionsRepository) *ProjectsHandler {
 	return &ProjectsHandler{
 		trackersRepo: trackersRepo,
+		preferences:  preferences,
+		holidays:     holidays,
+		rates:        ratesRepo,
+		versions:     versions,
 	}
 }
 
 func (h *ProjectsHandler) Redirect(c *gin.Context) {
-	now := time.Now()
+	now := time.Now().In(location(c))
 	url := "/" + strconv.Itoa(now.Year()) + "/" + strconv.Itoa(int(now.Month())) + "/projects"
-	c.Redirect(http.StatusFound, url)
+	c.Redirect(http.StatusFound, redirectURL(c, url))
 }
 
 func (h *ProjectsHandler) Index(c *gin.Context) {
@@ -41,18 +50,62 @@ func (h *ProjectsHandler) Index(c *gin.Context) {
 		return
 	}
 	
-	date := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	date := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, location(c))
 	
-	projectTimes, err := h.trackersRepo.GetMonthlyTimeByProject(date)
+	snapshots, err := h.trackersRepo.MonthSnapshots(date)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get project times"})
 		return
 	}
+	projectTimes := snapshots.Projects()
 	
-	c.JSON(http.StatusOK, gin.H{
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+	
+	nextMonth := date.AddDate(0, 1, 0)
+	calendar, err := h.holidays.Calendar(date, nextMonth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+		return
+	}
+	
+	// Every day of an entry is billed at the rate in effect on it, as far
+	// as the trackers tell the days of each project apart
+	rateTable, err := h.rates.Table()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rates"})
+		return
+	}
+	earnings := rateTable.Price(snapshots.Intervals().Clip(date, nextMonth), projectTimes, date.Location(), lastBilledDay(date, nextMonth))
+	
+	now := time.Now().In(date.Location())
+	stale := snapshots.Stale(now)
+	
+	response := gin.H{
 		"year":     year,
 		"month":    month,
-		"projects": projectTimes.ToArray(),
+		"projects": projectsWithAmounts(projectTimes, earnings),
 		"total_hours": projectTimes.GetHours(),
-	})
+		"monthly_goal": round2(calendar.MonthlyGoal(date)),
+		"stale":    stale,
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for key, value := range periodProgress(calendar, date, nextMonth, today, snapshots.Hours(date, nextMonth)) {
+		response[key] = value
+	}
+	
+	response["earnings"] = earningsSummary(calendar, date, today, earnings, prefs)
+	
+	projection := forecast.Build(calendar, date, nextMonth, today, snapshots.Intervals().GetDailyHours(date))
+	response["burn_up"] = projection.BurnUp
+	response["forecast"] = projection.Forecast
+	
+	modified, ok := lastModified(c, h.versions, snapshots.FetchedAt())
+	if !ok {
+		return
+	}
+	conditionalJSON(c, response, modified, monthFinished(date) && len(stale) == 0)
 }
\ No newline at end of file
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"myspace/backend/internal/rates"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/types"
+	"myspace/backend/internal/workdays"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RatesHandler struct {
+	rates        *repositories.RatesRepository
+	trackersRepo *repositories.TrackersRepository
+	preferences  *repositories.PreferencesRepository
+}
+
+func NewRatesHandler(ratesRepo *repositories.RatesRepository, trackersRepo *repositories.TrackersRepository, preferences *repositories.PreferencesRepository) *RatesHandler {
+	return &RatesHandler{
+		rates:        ratesRepo,
+		trackersRepo: trackersRepo,
+		preferences:  preferences,
+	}
+}
+
+// rateRequest is the body of Create and Update.
+type rateRequest struct {
+	Source        *string  `json:"source"`
+	ProjectID     *string  `json:"project_id"`
+	Rate          *float64 `json:"rate"`
+	Currency      *string  `json:"currency"`
+	EffectiveFrom *string  `json:"effective_from"`
+}
+
+func (h *RatesHandler) Index(c *gin.Context) {
+	list, err := h.rates.All()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rates"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(list))
+	for _, rate := range list {
+		items = append(items, rateMap(rate))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rates": items})
+}
+
+// Create adds a rate; without a project_id it applies to every project of
+// the source that has no rate of its own, and without a currency it is in
+// the preferred one.
+func (h *RatesHandler) Create(c *gin.Context) {
+	var request rateRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if request.Source == nil || request.Rate == nil || request.EffectiveFrom == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source, rate and effective_from are required"})
+		return
+	}
+
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+
+	rate := rates.Rate{Currency: prefs.Currency}
+	if !h.apply(c, &request, &rate) {
+		return
+	}
+	h.save(c, &rate, http.StatusCreated)
+}
+
+// Update changes the fields present in the body.
+func (h *RatesHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	rate, found, err := h.rates.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rate"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rate not found"})
+		return
+	}
+
+	var request rateRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if !h.apply(c, &request, &rate) {
+		return
+	}
+	h.save(c, &rate, http.StatusOK)
+}
+
+func (h *RatesHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	deleted, err := h.rates.Delete(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete rate"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rate not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true, "id": id})
+}
+
+func (h *RatesHandler) save(c *gin.Context, rate *rates.Rate, status int) {
+	err := h.rates.Save(rate)
+	if errors.Is(err, repositories.ErrRateExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrRateNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rate not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save rate"})
+		return
+	}
+
+	c.JSON(status, rateMap(*rate))
+}
+
+// apply copies the fields present in request onto rate, answering 400 and
+// returning false when one is invalid.
+func (h *RatesHandler) apply(c *gin.Context, request *rateRequest, rate *rates.Rate) bool {
+	if request.Source != nil {
+		known := false
+		for _, source := range h.trackersRepo.Sources() {
+			known = known || source == *request.Source
+		}
+		if !known {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source must be a configured tracker"})
+			return false
+		}
+		rate.Source = *request.Source
+	}
+
+	if request.ProjectID != nil {
+		rate.ProjectID = *request.ProjectID
+	}
+
+	if request.Rate != nil {
+		if *request.Rate < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rate must not be negative"})
+			return false
+		}
+		rate.Hourly = *request.Rate
+	}
+
+	if request.Currency != nil {
+		if !types.IsCurrency(*request.Currency) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "currency must be a three-letter ISO 4217 code"})
+			return false
+		}
+		rate.Currency = *request.Currency
+	}
+
+	if request.EffectiveFrom != nil {
+		effectiveFrom, err := time.Parse("2006-01-02", *request.EffectiveFrom)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "effective_from must be a date, YYYY-MM-DD"})
+			return false
+		}
+		rate.EffectiveFrom = effectiveFrom
+	}
+	return true
+}
+
+func rateMap(rate rates.Rate) gin.H {
+	var projectID interface{}
+	if rate.ProjectID != "" {
+		projectID = rate.ProjectID
+	}
+
+	return gin.H{
+		"id":             rate.ID,
+		"source":         rate.Source,
+		"project_id":     projectID,
+		"rate":           rate.Hourly,
+		"currency":       rate.Currency,
+		"effective_from": rate.EffectiveFrom.Format("2006-01-02"),
+	}
+}
+
+// projectsWithAmounts adds each project's earnings to its row: amount and
+// currency, or nulls when no rate applied or the project earned in more
+// than one currency (see the totals).
+func projectsWithAmounts(projectTimes types.ProjectTimeList, earnings rates.Earnings) []map[string]interface{} {
+	rows := projectTimes.ToArray()
+	for i, item := range projectTimes {
+		rows[i]["amount"] = nil
+		rows[i]["currency"] = nil
+
+		amounts := earnings.Projects[item.Source+":"+item.ProjectID]
+		if len(amounts) != 1 {
+			continue
+		}
+		for currency, amount := range amounts {
+			rows[i]["amount"] = round2(amount)
+			rows[i]["currency"] = currency
+		}
+	}
+	return rows
+}
+
+// earningsSummary measures what was earned, in the preferred currency,
+// against the monthly earnings target. The target is expected to be
+// earned like the hours goal: expected is its share by the end of day, in
+// step with the expected hours of the month starting at som.
+func earningsSummary(calendar *workdays.Calendar, som, day time.Time, earnings rates.Earnings, prefs types.Preferences) gin.H {
+	nextMonth := som.AddDate(0, 1, 0)
+	until := day.AddDate(0, 0, 1)
+	if until.After(nextMonth) {
+		until = nextMonth
+	}
+
+	expected := 0.0
+	if goal := calendar.ExpectedHours(som, nextMonth); goal > 0 && until.After(som) {
+		expected = prefs.EarningsTarget * calendar.ExpectedHours(som, until) / goal
+	}
+
+	totals := gin.H{}
+	for currency, amount := range earnings.Totals {
+		totals[currency] = round2(amount)
+	}
+
+	earned := earnings.Totals[prefs.Currency]
+	return gin.H{
+		"currency":      prefs.Currency,
+		"earned":        round2(earned),
+		"target":        prefs.EarningsTarget,
+		"expected":      round2(expected),
+		"percent":       percent(earned, prefs.EarningsTarget),
+		"totals":        totals,
+		"unrated_hours": round2(earnings.UnratedHours),
+	}
+}
+
+// lastBilledDay is the day the rates of a range [from, to) are taken from
+// when its entries can't be priced day by day: its last day, or today while
+// the range is still running.
+func lastBilledDay(from, to time.Time) time.Time {
+	now := time.Now().In(from.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, from.Location())
+	if today.Before(to) && !today.Before(from) {
+		return today
+	}
+	return to.AddDate(0, 0, -1)
+}
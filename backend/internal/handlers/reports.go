@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/types"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportsHandler struct {
+	trackersRepo *repositories.TrackersRepository
+}
+
+func NewReportsHandler(trackersRepo *repositories.TrackersRepository) *ReportsHandler {
+	return &ReportsHandler{
+		trackersRepo: trackersRepo,
+	}
+}
+
+// Index reports on an arbitrary range, e.g.
+// /reports?from=2024-01-01&to=2024-03-31&group_by=project,week&sources=clockify
+// Both dates are inclusive; the range defaults to the last 30 days.
+func (h *ReportsHandler) Index(c *gin.Context) {
+	loc := location(c)
+	today := time.Now().In(loc)
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+
+	to := today
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+
+	groupBy := splitList(c.DefaultQuery("group_by", "day"))
+	for _, dimension := range groupBy {
+		if !types.ReportDimensions[dimension] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group_by: " + dimension})
+			return
+		}
+	}
+
+	sources := splitList(c.Query("sources"))
+
+	// The snapshots of every month the range touches, so a provider that is
+	// down falls back to its last known data instead of being left out
+	end := to.AddDate(0, 0, 1)
+	snapshots, err := h.trackersRepo.RangeSnapshots(from, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get intervals"})
+		return
+	}
+	snapshots = snapshots.Only(sources)
+	projectTimes := snapshots.Intervals().Clip(from, end)
+
+	totalSeconds := 0
+	for _, item := range projectTimes {
+		totalSeconds += item.Seconds
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":                     from.Format("2006-01-02"),
+		"to":                       to.Format("2006-01-02"),
+		"group_by":                 groupBy,
+		"sources":                  sources,
+		"total_seconds":            totalSeconds,
+		"total_hours":              projectTimes.GetHours(),
+		"groups":                   projectTimes.GroupBy(groupBy, loc),
+		"sources_without_projects": projectTimes.SourcesWithoutProjects(),
+		"stale":                    snapshots.Stale(time.Now()),
+	})
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/workdays"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchedulesHandler struct {
+	schedules *repositories.SchedulesRepository
+}
+
+func NewSchedulesHandler(schedules *repositories.SchedulesRepository) *SchedulesHandler {
+	return &SchedulesHandler{
+		schedules: schedules,
+	}
+}
+
+// scheduleRequest is the body of Create and Update; hours are keyed by
+// lowercase weekday name.
+type scheduleRequest struct {
+	Name          *string            `json:"name"`
+	EffectiveFrom *string            `json:"effective_from"`
+	Hours         map[string]float64 `json:"hours"`
+}
+
+func (h *SchedulesHandler) Index(c *gin.Context) {
+	profiles, err := h.schedules.All()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get schedules"})
+		return
+	}
+
+	list := make([]gin.H, 0, len(profiles))
+	for _, profile := range profiles {
+		list = append(list, profileMap(profile))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedules": list})
+}
+
+// Create adds a profile; weekdays left out of hours are days off.
+func (h *SchedulesHandler) Create(c *gin.Context) {
+	var request scheduleRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if request.EffectiveFrom == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effective_from is required"})
+		return
+	}
+
+	var profile workdays.Profile
+	if !applySchedule(c, &request, &profile) {
+		return
+	}
+	h.save(c, &profile, http.StatusCreated)
+}
+
+// Update changes the fields present in the body; weekdays left out of hours
+// keep theirs.
+func (h *SchedulesHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	profile, found, err := h.schedules.Get(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get schedule"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+
+	var request scheduleRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if !applySchedule(c, &request, &profile) {
+		return
+	}
+	h.save(c, &profile, http.StatusOK)
+}
+
+func (h *SchedulesHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return
+	}
+
+	deleted, err := h.schedules.Delete(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schedule"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true, "id": id})
+}
+
+func (h *SchedulesHandler) save(c *gin.Context, profile *workdays.Profile, status int) {
+	err := h.schedules.Save(profile)
+	if errors.Is(err, repositories.ErrProfileExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrProfileNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save schedule"})
+		return
+	}
+
+	c.JSON(status, profileMap(*profile))
+}
+
+// applySchedule copies the fields present in request onto profile,
+// answering 400 and returning false when one is invalid.
+func applySchedule(c *gin.Context, request *scheduleRequest, profile *workdays.Profile) bool {
+	if request.Name != nil {
+		profile.Name = *request.Name
+	}
+
+	if request.EffectiveFrom != nil {
+		effectiveFrom, err := time.Parse("2006-01-02", *request.EffectiveFrom)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "effective_from must be a date, YYYY-MM-DD"})
+			return false
+		}
+		profile.EffectiveFrom = effectiveFrom
+	}
+
+	for name, hours := range request.Hours {
+		weekday, ok := workdays.ParseWeekday(name)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hours contains unknown weekday " + strconv.Quote(name)})
+			return false
+		}
+		if hours < 0 || hours > 24 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hours must be between 0 and 24"})
+			return false
+		}
+		profile.Hours[weekday] = hours
+	}
+
+	if profile.WeeklyHours() <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a schedule needs hours on at least one weekday"})
+		return false
+	}
+	return true
+}
+
+func profileMap(profile workdays.Profile) gin.H {
+	hours := gin.H{}
+	for day := time.Monday; day <= time.Saturday; day++ {
+		hours[workdays.WeekdayName(day)] = profile.Hours[day]
+	}
+	hours["sunday"] = profile.Hours[time.Sunday]
+
+	return gin.H{
+		"id":             profile.ID,
+		"name":           profile.Name,
+		"effective_from": profile.EffectiveFrom.Format("2006-01-02"),
+		"hours":          hours,
+		"weekly_hours":   profile.WeeklyHours(),
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"myspace/backend/internal/repositories"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	preferences *repositories.PreferencesRepository
+}
+
+func NewSettingsHandler(preferences *repositories.PreferencesRepository) *SettingsHandler {
+	return &SettingsHandler{
+		preferences: preferences,
+	}
+}
+
+func (h *SettingsHandler) Index(c *gin.Context) {
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// Update changes the fields present in the body and keeps the others.
+func (h *SettingsHandler) Update(c *gin.Context) {
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+
+	if err := json.NewDecoder(c.Request.Body).Decode(&prefs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+
+	if err := prefs.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.preferences.Update(prefs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
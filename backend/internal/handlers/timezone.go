@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const locationKey = "location"
+
+// Timezone resolves the user's timezone from the X-Timezone header or the
+// tz query parameter, falling back to the preferred timezone. Handlers read
+// it back with location(c) so day and month boundaries match the user's
+// calendar instead of the server's.
+func Timezone(defaultLocation func() *time.Location) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.GetHeader("X-Timezone")
+		if name == "" {
+			name = c.Query("tz")
+		}
+
+		loc := defaultLocation()
+		if name != "" {
+			parsed, err := time.LoadLocation(name)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+				return
+			}
+			loc = parsed
+		}
+
+		c.Set(locationKey, loc)
+		c.Next()
+	}
+}
+
+func location(c *gin.Context) *time.Location {
+	if loc, ok := c.Get(locationKey); ok {
+		return loc.(*time.Location)
+	}
+	return time.UTC
+}
+
+// redirectURL keeps the query string (e.g. tz) when redirecting.
+func redirectURL(c *gin.Context, path string) string {
+	if c.Request.URL.RawQuery != "" {
+		return path + "?" + c.Request.URL.RawQuery
+	}
+	return path
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"math"
+	"myspace/backend/internal/forecast"
 	"myspace/backend/internal/repositories"
 	"net/http"
 	"strconv"
@@ -12,18 +12,28 @@ import (
 
 type TodayHandler struct {
 	trackersRepo *repositories.TrackersRepository
+	preferences  *repositories.PreferencesRepository
+	holidays     *repositories.HolidaysRepository
+	overtime     *repositories.OvertimeRepository
+	rates        *repositories.RatesRepository
+	versions     *repositories.VersionsRepository
 }
 
-func NewTodayHandler(trackersRepo *repositories.TrackersRepository) *TodayHandler {
+func NewTodayHandler(trackersRepo *repositories.TrackersRepository, preferences *repositories.PreferencesRepository, holidays *repositories.HolidaysRepository, overtime *repositories.OvertimeRepository, ratesRepo *repositories.RatesRepository, versions *repositories.VersionsRepository) *TodayHandler {
 	return &TodayHandler{
 		trackersRepo: trackersRepo,
+		preferences:  preferences,
+		holidays:     holidays,
+		overtime:     overtime,
+		rates:        ratesRepo,
+		versions:     versions,
 	}
 }
 
 func (h *TodayHandler) Redirect(c *gin.Context) {
-	now := time.Now()
+	now := time.Now().In(location(c))
 	url := "/" + strconv.Itoa(now.Year()) + "/" + strconv.Itoa(int(now.Month())) + "/" + strconv.Itoa(now.Day())
-	c.Redirect(http.StatusFound, url)
+	c.Redirect(http.StatusFound, redirectURL(c, url))
 }
 
 func (h *TodayHandler) Index(c *gin.Context) {
@@ -49,16 +59,18 @@ func (h *TodayHandler) Index(c *gin.Context) {
 		return
 	}
 
-	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	loc := location(c)
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
 	tomorrow := date.AddDate(0, 0, 1)
-	now := time.Now()
+	now := time.Now().In(loc)
 
-	// Get today's hours
-	todayHours, err := h.trackersRepo.Hours(date, tomorrow)
+	// One snapshot per tracker covers both the day and the month so far
+	snapshots, err := h.trackersRepo.MonthSnapshots(date)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hours"})
 		return
 	}
+	todayHours := snapshots.Hours(date, tomorrow)
 
 	// Get running hours
 	runningHours, err := h.trackersRepo.RunningHours()
@@ -68,13 +80,9 @@ func (h *TodayHandler) Index(c *gin.Context) {
 	}
 
 	// Get month hours
-	monthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	monthEnd := date.AddDate(0, 0, 1) // End of the current day
-	monthHours, err := h.trackersRepo.Hours(monthStart, monthEnd)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get month hours"})
-		return
-	}
+	monthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	nextMonth := monthStart.AddDate(0, 1, 0)
+	monthHours := snapshots.Hours(monthStart, tomorrow)
 
 	// Add running hours if it's today
 	isToday := date.Year() == now.Year() && date.Month() == now.Month() && date.Day() == now.Day()
@@ -82,21 +90,27 @@ func (h *TodayHandler) Index(c *gin.Context) {
 		monthHours += runningHours
 	}
 
-	// Settings (hardcoded for now - should come from database)
-	dailyGoal := 8.0
-	monthlyGoal := 160.0
+	prefs, err := h.preferences.Get()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get settings"})
+		return
+	}
+
+	// Goals, expected hours and the forecast follow the working days and
+	// the schedule profile in effect on each of them
+	calendar, err := h.holidays.Calendar(monthStart, nextMonth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+		return
+	}
+	dailyGoal := calendar.Target(date)
+	monthlyGoal := calendar.MonthlyGoal(monthStart)
 
 	// Calculate percentages (rounded to 2 decimal places)
-	todayPercent := math.Round((todayHours/dailyGoal)*10000) / 100
-	monthPercent := math.Round((monthHours/monthlyGoal)*10000) / 100
+	todayPercent := percent(todayHours, dailyGoal)
+	monthPercent := percent(monthHours, monthlyGoal)
 
-	// Calculate pace (simplified)
-	daysInMonth := float64(time.Date(year, time.Month(month+1), 0, 0, 0, 0, 0, time.UTC).Day())
-	dayOfMonth := float64(day)
-	remainingDays := daysInMonth - dayOfMonth
-	expectedHours := remainingDays * dailyGoal
-	remainingHours := monthlyGoal - monthHours
-	pace := expectedHours - remainingHours
+	isWorkingDay, holiday := dayOff(calendar, date)
 
 	// Navigation links
 	prevDay := date.AddDate(0, 0, -1)
@@ -117,16 +131,74 @@ func (h *TodayHandler) Index(c *gin.Context) {
 		"next_link":  "/" + strconv.Itoa(nextDay.Year()) + "/" + strconv.Itoa(int(nextDay.Month())) + "/" + strconv.Itoa(nextDay.Day()),
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"date":          date.Format("2006-01-02"),
-		"hours":         todayHours,
-		"running_hours": runningHours,
-		"today_percent": todayPercent,
-		"month_percent": monthPercent,
-		"month_hours":   monthHours,
-		"pace":          pace,
-		"daily_goal":    dailyGoal,
-		"is_today":      isToday,
-		"nav":           nav,
-	})
+	// A running timer changes the answer without any new snapshot
+	fetched := snapshots.FetchedAt()
+	if runningHours > 0 {
+		fetched = now
+	}
+	stale := snapshots.Stale(now)
+
+	response := gin.H{
+		"date":           date.Format("2006-01-02"),
+		"hours":          todayHours,
+		"running_hours":  runningHours,
+		"today_percent":  todayPercent,
+		"month_percent":  monthPercent,
+		"month_hours":    monthHours,
+		"daily_goal":     dailyGoal,
+		"monthly_goal":   round2(monthlyGoal),
+		"is_today":       isToday,
+		"is_working_day": isWorkingDay,
+		"holiday":        holiday,
+		"leave":          leaveList(calendar.Leave(date)),
+		"stale":          stale,
+		"nav":            nav,
+	}
+	for key, value := range periodProgress(calendar, monthStart, nextMonth, date, monthHours) {
+		response[key] = value
+	}
+
+	// The burn-up counts a running timer towards today like month_hours does
+	dailyHours := snapshots.Intervals().GetDailyHours(date)
+	if isToday && runningHours > 0 {
+		hours := runningHours
+		if h := dailyHours[date.Format("2006-01-02")]; h != nil {
+			hours += *h
+		}
+		dailyHours[date.Format("2006-01-02")] = &hours
+	}
+	projection := forecast.Build(calendar, monthStart, nextMonth, date, dailyHours)
+	response["burn_up"] = projection.BurnUp
+	response["forecast"] = projection.Forecast
+
+	// The ledger runs to the end of the day; a running timer counts like it
+	// does for month_hours
+	ledger, err := h.overtime.Ledger(date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get overtime"})
+		return
+	}
+	overtimeBalance := ledger.Balance
+	if isToday {
+		overtimeBalance = round2(overtimeBalance + runningHours)
+	}
+	response["overtime_balance"] = overtimeBalance
+
+	// Earnings run to the end of the day like month_hours, leaving out
+	// running timers, which carry no project on every tracker
+	rateTable, err := h.rates.Table()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rates"})
+		return
+	}
+	entries, projects := snapshots.Intervals(), snapshots.Projects()
+	earnings := earningsSummary(calendar, monthStart, date, rateTable.Price(entries.Clip(monthStart, tomorrow), projects, loc, date), prefs)
+	earnings["today"] = round2(rateTable.Price(entries.Clip(date, tomorrow), projects, loc, date).Totals[prefs.Currency])
+	response["earnings"] = earnings
+
+	modified, ok := lastModified(c, h.versions, fetched)
+	if !ok {
+		return
+	}
+	conditionalJSON(c, response, modified, monthFinished(date) && len(stale) == 0)
 }
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"myspace/backend/internal/interfaces"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/syncer"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBody caps how much of a webhook request is read.
+const maxWebhookBody = 1 << 20
+
+type WebhooksHandler struct {
+	trackersRepo *repositories.TrackersRepository
+	sync         *syncer.Engine
+	logger       *slog.Logger
+}
+
+func NewWebhooksHandler(trackersRepo *repositories.TrackersRepository, sync *syncer.Engine, logger *slog.Logger) *WebhooksHandler {
+	return &WebhooksHandler{
+		trackersRepo: trackersRepo,
+		sync:         sync,
+		logger:       logger.With("component", "webhooks"),
+	}
+}
+
+// Receive verifies and stores a change pushed by a tracker, then drops its
+// cached answers so the change shows up right away.
+func (h *WebhooksHandler) Receive(c *gin.Context) {
+	source := c.Param("source")
+
+	receiver, ok := h.trackersRepo.WebhookReceiver(source)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhooks not supported for this source"})
+		return
+	}
+
+	if handshaker, ok := receiver.(interfaces.WebhookHandshaker); ok {
+		handshake, err := handshaker.Handshake(c.Request.Header, c.Writer.Header())
+		if err != nil {
+			h.logger.Warn("rejected webhook handshake", "tracker", source, "error", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid hook secret"})
+			return
+		}
+		if handshake {
+			c.Status(http.StatusOK)
+			return
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+		return
+	}
+
+	if err := receiver.VerifyWebhook(c.Request.Header, body); err != nil {
+		h.logger.Warn("rejected webhook", "tracker", source, "error", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	event, err := receiver.ParseWebhook(c.Request.Header, body, h.sync.Location())
+	if err != nil {
+		h.logger.Warn("invalid webhook payload", "tracker", source, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
+		return
+	}
+
+	// Ignored payloads, like running timers or other users' entries, change
+	// nothing, so the cached answers are only dropped once one was stored
+	if event != nil {
+		if err := h.sync.Apply(event); err != nil {
+			h.logger.Error("failed to apply webhook", "tracker", source, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store entry"})
+			return
+		}
+		h.trackersRepo.InvalidateCache(source)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"received": true,
+		"stored":   event != nil,
+	})
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"math"
+	"myspace/backend/internal/repositories"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WeekHandler struct {
+	trackersRepo *repositories.TrackersRepository
+	holidays     *repositories.HolidaysRepository
+}
+
+func NewWeekHandler(trackersRepo *repositories.TrackersRepository, holidays *repositories.HolidaysRepository) *WeekHandler {
+	return &WeekHandler{
+		trackersRepo: trackersRepo,
+		holidays:     holidays,
+	}
+}
+
+func (h *WeekHandler) Redirect(c *gin.Context) {
+	year, week := time.Now().In(location(c)).ISOWeek()
+	c.Redirect(http.StatusFound, redirectURL(c, weekLink(year, week)))
+}
+
+func (h *WeekHandler) Index(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return
+	}
+
+	week, err := strconv.Atoi(c.Param("week"))
+	if err != nil || week < 1 || week > isoWeeksInYear(year) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week"})
+		return
+	}
+
+	loc := location(c)
+	from := isoWeekStart(year, week, loc)
+	to := from.AddDate(0, 0, 7)
+	now := time.Now().In(loc)
+
+	// The week's months from their snapshots, shared with the month views
+	// and falling back like them when a provider is down
+	snapshots, err := h.trackersRepo.RangeSnapshots(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get intervals"})
+		return
+	}
+	intervals := snapshots.Intervals().Clip(from, to)
+	dailyHours := intervals.GetDailyHoursBetween(from, to)
+
+	var days []map[string]interface{}
+	totalHours := 0.0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		hours := dailyHours[d.Format("2006-01-02")]
+		if hours != nil {
+			totalHours += *hours
+		}
+
+		days = append(days, map[string]interface{}{
+			"date":     d.Format("2006-01-02"),
+			"weekday":  d.Format("Monday"),
+			"hours":    hours,
+			"is_today": d.Year() == now.Year() && d.YearDay() == now.YearDay(),
+		})
+	}
+	totalHours = math.Round(totalHours*100) / 100
+
+	calendar, err := h.holidays.Calendar(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+		return
+	}
+
+	// The week's goal adds up the targets of its days
+	weeklyGoal := round2(calendar.Goal(from, to))
+
+	prevYear, prevWeek := from.AddDate(0, 0, -7).ISOWeek()
+	nextYear, nextWeek := to.ISOWeek()
+
+	nav := gin.H{
+		"title":      "Week " + strconv.Itoa(week),
+		"range":      from.Format("Jan 2") + " – " + to.AddDate(0, 0, -1).Format("Jan 2"),
+		"month_link": "/" + strconv.Itoa(from.Year()) + "/" + strconv.Itoa(int(from.Month())) + "/calendar",
+		"prev_link":  weekLink(prevYear, prevWeek),
+		"next_link":  weekLink(nextYear, nextWeek),
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"year":        year,
+		"week":        week,
+		"from":        from.Format("2006-01-02"),
+		"to":          to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"days":        days,
+		"projects":    intervals.GroupByProject().ToArray(),
+		"total_hours": totalHours,
+		"weekly_goal": weeklyGoal,
+		"progress":    percent(totalHours, weeklyGoal),
+		"stale":       snapshots.Stale(now),
+		"nav":         nav,
+	})
+}
+
+// isoWeekStart returns the Monday of the given ISO week. Week 1 is the week
+// containing January 4th.
+func isoWeekStart(year, week int, loc *time.Location) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+(week-1)*7)
+}
+
+func isoWeeksInYear(year int) int {
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+func weekLink(year, week int) string {
+	return "/" + strconv.Itoa(year) + "/week/" + strconv.Itoa(week)
+}
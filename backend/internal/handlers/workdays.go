@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"math"
+	"myspace/backend/internal/workdays"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// periodProgress measures hours against the goal of the period [from, to),
+// usually a month: each day's target, from its schedule profile or the
+// daily and monthly goals. Leave takes its share of the goal away. day is
+// the day being looked at: expected hours run to the end of it and the
+// remaining working days start with it. Days before the period expect
+// nothing yet, days after it leave nothing remaining.
+func periodProgress(calendar *workdays.Calendar, from, to, day time.Time, hours float64) gin.H {
+	clamp := func(t time.Time) time.Time {
+		if t.Before(from) {
+			return from
+		}
+		if t.After(to) {
+			return to
+		}
+		return t
+	}
+
+	remaining := calendar.AvailableDays(clamp(day), to)
+	adjustedGoal := calendar.ExpectedHours(from, to)
+	expectedHours := calendar.ExpectedHours(from, clamp(day.AddDate(0, 0, 1)))
+
+	// Nothing is left to spread the missing hours over once the period's
+	// working days are used up.
+	var requiredAverage interface{}
+	if remaining > 0 {
+		requiredAverage = round2(math.Max(adjustedGoal-hours, 0) / remaining)
+	}
+
+	return gin.H{
+		"working_days":           calendar.WorkingDays(from, to),
+		"leave_days":             round2(calendar.LeaveDays(from, to)),
+		"adjusted_goal":          round2(adjustedGoal),
+		"remaining_working_days": round2(remaining),
+		"expected_hours":         round2(expectedHours),
+		"required_daily_average": requiredAverage,
+	}
+}
+
+// dayOff describes whether day is worked and which holiday falls on it.
+func dayOff(calendar *workdays.Calendar, day time.Time) (bool, interface{}) {
+	if holiday, ok := calendar.Holiday(day); ok {
+		return calendar.IsWorkingDay(day), holiday.Name
+	}
+	return calendar.IsWorkingDay(day), nil
+}
+
+func leaveList(leave []workdays.Leave) []gin.H {
+	list := make([]gin.H, 0, len(leave))
+	for _, entry := range leave {
+		list = append(list, gin.H{
+			"id":       entry.ID,
+			"date":     entry.Date.Format("2006-01-02"),
+			"type":     entry.Type,
+			"fraction": entry.Fraction,
+			"note":     entry.Note,
+		})
+	}
+	return list
+}
+
+// percent is hours as a share of goal, 0 when there is no goal.
+func percent(hours, goal float64) float64 {
+	if goal <= 0 {
+		return 0
+	}
+	return math.Round((hours/goal)*10000) / 100
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
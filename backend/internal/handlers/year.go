@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"math"
+	"myspace/backend/internal/repositories"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type YearHandler struct {
+	trackersRepo *repositories.TrackersRepository
+	holidays     *repositories.HolidaysRepository
+}
+
+func NewYearHandler(trackersRepo *repositories.TrackersRepository, holidays *repositories.HolidaysRepository) *YearHandler {
+	return &YearHandler{
+		trackersRepo: trackersRepo,
+		holidays:     holidays,
+	}
+}
+
+func (h *YearHandler) Index(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return
+	}
+
+	loc := location(c)
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	to := from.AddDate(1, 0, 0)
+	now := time.Now().In(loc)
+
+	// The snapshots of the months up to this one, shared with the month
+	// views and falling back like them when a provider is down
+	until := to
+	if next := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, 1, 0); next.Before(until) {
+		until = next
+	}
+	snapshots, err := h.trackersRepo.RangeSnapshots(from, until)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get intervals"})
+		return
+	}
+	projectTimes := snapshots.Intervals().Clip(from, to)
+	dailyHours := projectTimes.GetDailyHoursBetween(from, to)
+
+	calendar, err := h.holidays.Calendar(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get holidays"})
+		return
+	}
+
+	var heatmap []map[string]interface{}
+	monthHours := make([]float64, 12)
+	yearToDate := 0.0
+
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		hours := dailyHours[d.Format("2006-01-02")]
+
+		value := 0.0
+		if hours != nil {
+			value = *hours
+		}
+		monthHours[d.Month()-1] += value
+		if !d.After(now) {
+			yearToDate += value
+		}
+
+		heatmap = append(heatmap, map[string]interface{}{
+			"date":  d.Format("2006-01-02"),
+			"hours": hours,
+			"level": heatmapLevel(value, calendar.Target(d)),
+		})
+	}
+
+	var months []map[string]interface{}
+	var best, worst map[string]interface{}
+	totalHours := 0.0
+	yearlyGoal := 0.0
+
+	for i, hours := range monthHours {
+		som := time.Date(year, time.Month(i+1), 1, 0, 0, 0, 0, loc)
+		hours = math.Round(hours*100) / 100
+		totalHours += hours
+		monthlyGoal := math.Round(calendar.MonthlyGoal(som)*100) / 100
+		yearlyGoal += monthlyGoal
+
+		month := map[string]interface{}{
+			"month":   i + 1,
+			"name":    som.Format("January"),
+			"hours":   hours,
+			"goal":    monthlyGoal,
+			"percent": percent(hours, monthlyGoal),
+			"link":    "/" + strconv.Itoa(year) + "/" + strconv.Itoa(i+1) + "/projects",
+		}
+		months = append(months, month)
+
+		// Only finished months compete for best and worst
+		if som.AddDate(0, 1, 0).After(now) {
+			continue
+		}
+		if best == nil || hours > best["hours"].(float64) {
+			best = month
+		}
+		if worst == nil || hours < worst["hours"].(float64) {
+			worst = month
+		}
+	}
+
+	nav := gin.H{
+		"year":      strconv.Itoa(year),
+		"prev_link": "/" + strconv.Itoa(year-1),
+		"next_link": "/" + strconv.Itoa(year+1),
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"year":               year,
+		"months":             months,
+		"total_hours":        math.Round(totalHours*100) / 100,
+		"year_to_date_hours": math.Round(yearToDate*100) / 100,
+		"yearly_goal":        math.Round(yearlyGoal*100) / 100,
+		"best_month":         best,
+		"worst_month":        worst,
+		"heatmap":            heatmap,
+		"stale":              snapshots.Stale(now),
+		"nav":                nav,
+	})
+}
+
+// heatmapLevel buckets a day's hours into 0-4 against the daily goal, like
+// the GitHub contributions graph.
+func heatmapLevel(hours, dailyGoal float64) int {
+	if hours <= 0 {
+		return 0
+	}
+	// Any work on a day without a goal counts in full
+	if dailyGoal <= 0 {
+		return 4
+	}
+
+	level := int(math.Ceil(hours / dailyGoal * 4))
+	if level > 4 {
+		level = 4
+	}
+	return level
+}
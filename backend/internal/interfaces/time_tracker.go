@@ -6,9 +6,14 @@ import (
 )
 
 type TimeTracker interface {
+	GetSource() string
 	GetUserID() string
 	GetSeconds(from, to time.Time) (int, error)
 	GetRunningSeconds() (int, error)
+	GetRunningTimer() (*types.RunningTimer, error)
 	GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error)
 	GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList, error)
+	GetTimeByProject(from, to time.Time) (types.ProjectTimeList, error)
+	GetIntervals(from, to time.Time) (types.ProjectTimeList, error)
+	GetMonthSnapshot(dayOfMonth time.Time) (*types.MonthSnapshot, error)
 }
\ No newline at end of file
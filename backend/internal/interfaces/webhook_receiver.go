@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"myspace/backend/internal/types"
+	"net/http"
+	"time"
+)
+
+// WebhookReceiver is implemented by trackers that can push entry changes.
+type WebhookReceiver interface {
+	// VerifyWebhook checks the request really comes from the provider.
+	VerifyWebhook(header http.Header, body []byte) error
+	// ParseWebhook normalizes the payload. A nil event means there is
+	// nothing to store, e.g. a timer was started.
+	ParseWebhook(header http.Header, body []byte, loc *time.Location) (*types.WebhookEvent, error)
+}
+
+// WebhookHandshaker is implemented by receivers whose provider confirms a
+// new hook with a handshake request before sending any events.
+type WebhookHandshaker interface {
+	// Handshake reports whether the request is a handshake. If it is, the
+	// handshake is verified and the headers of the answer are set on reply.
+	Handshake(header, reply http.Header) (bool, error)
+}
@@ -0,0 +1,143 @@
+package invoices
+
+import (
+	"fmt"
+	"math"
+	"myspace/backend/internal/rates"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Invoice states; an invoice only ever moves forward through them
+const (
+	Draft = "draft"
+	Sent  = "sent"
+	Paid  = "paid"
+)
+
+// Invoice is a bill for a client's hours in a period. Its figures are fixed
+// when it is created; Number, IssuedOn and DueOn are set when it is sent.
+// From and To are the first and last day billed. Dates are midnight UTC of
+// the calendar day, zero when not set.
+//
+// Sources and Projects are what the client is billed for: every project of
+// the sources, plus the projects named as "source:project_id".
+type Invoice struct {
+	ID            uint
+	Number        string
+	Status        string
+	Client        string
+	ClientAddress string
+	Sources       []string
+	Projects      []string
+	From          time.Time
+	To            time.Time
+	Currency      string
+	Lines         []Line
+	Subtotal      float64
+	TaxRate       float64
+	Tax           float64
+	Total         float64
+	Note          string
+	IssuedOn      time.Time
+	DueOn         time.Time
+	PaidOn        time.Time
+	CreatedAt     time.Time
+}
+
+// Line is one project's hours at one rate.
+type Line struct {
+	Source      string
+	ProjectID   string
+	Description string
+	Hours       float64
+	Rate        float64
+	Amount      float64
+}
+
+// CanMove reports whether an invoice in status from may move to status to.
+func CanMove(from, to string) bool {
+	return (from == Draft && to == Sent) || (from == Sent && to == Paid)
+}
+
+// Bills reports whether the invoice's client is billed for the project of
+// source.
+func (invoice Invoice) Bills(source, projectID string) bool {
+	for _, billed := range invoice.Sources {
+		if billed == source {
+			return true
+		}
+	}
+	for _, billed := range invoice.Projects {
+		if billed == source+":"+projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// Build lays out the lines of an invoice in its currency from what its
+// hours earned, keeping only the projects it bills, titles naming each
+// "source:project_id". Every hour billed must have a rate in the invoice's
+// currency; an error names a project that doesn't. Amounts are rounded per
+// line so the lines add up to the subtotal as printed, and TaxRate is a
+// percentage.
+func Build(invoice Invoice, earnings rates.Earnings, titles map[string]string) (Invoice, error) {
+	unrated := make([]string, 0, len(earnings.Unrated))
+	for key := range earnings.Unrated {
+		unrated = append(unrated, key)
+	}
+	sort.Strings(unrated)
+	for _, key := range unrated {
+		source, projectID, _ := strings.Cut(key, ":")
+		hours := earnings.Unrated[key]
+		if invoice.Bills(source, projectID) && math.Round(hours*100) > 0 {
+			return Invoice{}, fmt.Errorf("%s has %.2f hours without a rate", describe(key, titles), hours)
+		}
+	}
+
+	invoice.Lines = nil
+	invoice.Subtotal = 0
+	for _, line := range earnings.Lines {
+		if !invoice.Bills(line.Source, line.ProjectID) {
+			continue
+		}
+
+		key := line.Source + ":" + line.ProjectID
+		if line.Currency != invoice.Currency {
+			return Invoice{}, fmt.Errorf("%s is billed in %s, the invoice in %s", describe(key, titles), line.Currency, invoice.Currency)
+		}
+
+		hours := round2(line.Hours)
+		if hours == 0 {
+			continue
+		}
+		amount := round2(hours * line.Hourly)
+		invoice.Lines = append(invoice.Lines, Line{
+			Source:      line.Source,
+			ProjectID:   line.ProjectID,
+			Description: describe(key, titles),
+			Hours:       hours,
+			Rate:        line.Hourly,
+			Amount:      amount,
+		})
+		invoice.Subtotal += amount
+	}
+
+	invoice.Subtotal = round2(invoice.Subtotal)
+	invoice.Tax = round2(invoice.Subtotal * invoice.TaxRate / 100)
+	invoice.Total = round2(invoice.Subtotal + invoice.Tax)
+	return invoice, nil
+}
+
+func describe(key string, titles map[string]string) string {
+	if title := titles[key]; title != "" {
+		return title
+	}
+	return "(no project)"
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
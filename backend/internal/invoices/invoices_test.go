@@ -0,0 +1,197 @@
+package invoices
+
+import (
+	"myspace/backend/internal/rates"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	titles := map[string]string{
+		"clockify:web": "Website",
+		"clockify:app": "App",
+		"mayven:42":    "Support",
+	}
+
+	tests := []struct {
+		name     string
+		invoice  Invoice
+		earnings rates.Earnings
+		lines    []Line
+		subtotal float64
+		tax      float64
+		total    float64
+		err      string
+	}{
+		{
+			name:    "lines and totals",
+			invoice: Invoice{Currency: "EUR", Sources: []string{"clockify"}},
+			earnings: rates.Earnings{Lines: []rates.Line{
+				{Source: "clockify", ProjectID: "web", Hours: 10.5, Hourly: 95, Currency: "EUR"},
+				{Source: "clockify", ProjectID: "app", Hours: 2.25, Hourly: 80, Currency: "EUR"},
+			}},
+			lines: []Line{
+				{Source: "clockify", ProjectID: "web", Description: "Website", Hours: 10.5, Rate: 95, Amount: 997.5},
+				{Source: "clockify", ProjectID: "app", Description: "App", Hours: 2.25, Rate: 80, Amount: 180},
+			},
+			subtotal: 1177.5,
+			total:    1177.5,
+		},
+		{
+			name:    "hours and amounts rounded per line",
+			invoice: Invoice{Currency: "EUR", Sources: []string{"clockify"}, TaxRate: 19},
+			earnings: rates.Earnings{Lines: []rates.Line{
+				{Source: "clockify", ProjectID: "web", Hours: 1.333333, Hourly: 100, Currency: "EUR"},
+				{Source: "clockify", ProjectID: "app", Hours: 0.004, Hourly: 100, Currency: "EUR"},
+			}},
+			lines: []Line{
+				{Source: "clockify", ProjectID: "web", Description: "Website", Hours: 1.33, Rate: 100, Amount: 133},
+			},
+			subtotal: 133,
+			tax:      25.27,
+			total:    158.27,
+		},
+		{
+			name:    "tax rounded half away from zero",
+			invoice: Invoice{Currency: "EUR", Sources: []string{"clockify"}, TaxRate: 19},
+			earnings: rates.Earnings{Lines: []rates.Line{
+				{Source: "clockify", ProjectID: "web", Hours: 42.5, Hourly: 95, Currency: "EUR"},
+			}},
+			lines: []Line{
+				{Source: "clockify", ProjectID: "web", Description: "Website", Hours: 42.5, Rate: 95, Amount: 4037.5},
+			},
+			subtotal: 4037.5,
+			tax:      767.13,
+			total:    4804.63,
+		},
+		{
+			name:    "only the projects billed",
+			invoice: Invoice{Currency: "EUR", Projects: []string{"mayven:42"}},
+			earnings: rates.Earnings{
+				Lines: []rates.Line{
+					{Source: "clockify", ProjectID: "web", Hours: 3, Hourly: 95, Currency: "EUR"},
+					{Source: "mayven", ProjectID: "42", Hours: 2, Hourly: 60, Currency: "EUR"},
+				},
+				Unrated: map[string]float64{"mayven:7": 4},
+			},
+			lines: []Line{
+				{Source: "mayven", ProjectID: "42", Description: "Support", Hours: 2, Rate: 60, Amount: 120},
+			},
+			subtotal: 120,
+			total:    120,
+		},
+		{
+			name:    "project without a title",
+			invoice: Invoice{Currency: "EUR", Sources: []string{"everhour"}},
+			earnings: rates.Earnings{Lines: []rates.Line{
+				{Source: "everhour", ProjectID: "ev:1", Hours: 1, Hourly: 50, Currency: "EUR"},
+			}},
+			lines: []Line{
+				{Source: "everhour", ProjectID: "ev:1", Description: "(no project)", Hours: 1, Rate: 50, Amount: 50},
+			},
+			subtotal: 50,
+			total:    50,
+		},
+		{
+			name:    "unrated hours billed",
+			invoice: Invoice{Currency: "EUR", Sources: []string{"clockify"}},
+			earnings: rates.Earnings{
+				Lines:   []rates.Line{{Source: "clockify", ProjectID: "web", Hours: 3, Hourly: 95, Currency: "EUR"}},
+				Unrated: map[string]float64{"clockify:app": 1.5},
+			},
+			err: "App has 1.50 hours without a rate",
+		},
+		{
+			name:    "unrated hours below a cent of an hour",
+			invoice: Invoice{Currency: "EUR", Sources: []string{"clockify"}},
+			earnings: rates.Earnings{
+				Lines:   []rates.Line{{Source: "clockify", ProjectID: "web", Hours: 3, Hourly: 95, Currency: "EUR"}},
+				Unrated: map[string]float64{"clockify:app": 0.004},
+			},
+			lines: []Line{
+				{Source: "clockify", ProjectID: "web", Description: "Website", Hours: 3, Rate: 95, Amount: 285},
+			},
+			subtotal: 285,
+			total:    285,
+		},
+		{
+			name:    "rate in another currency",
+			invoice: Invoice{Currency: "EUR", Sources: []string{"clockify"}},
+			earnings: rates.Earnings{Lines: []rates.Line{
+				{Source: "clockify", ProjectID: "web", Hours: 3, Hourly: 100, Currency: "USD"},
+			}},
+			err: "Website is billed in USD, the invoice in EUR",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			invoice, err := Build(test.invoice, test.earnings, titles)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("Build() error = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			if len(invoice.Lines) != len(test.lines) {
+				t.Fatalf("Build() lines = %+v, want %+v", invoice.Lines, test.lines)
+			}
+			for i, line := range invoice.Lines {
+				if line != test.lines[i] {
+					t.Errorf("line %d = %+v, want %+v", i, line, test.lines[i])
+				}
+			}
+			if invoice.Subtotal != test.subtotal || invoice.Tax != test.tax || invoice.Total != test.total {
+				t.Errorf("Build() subtotal, tax, total = %v, %v, %v, want %v, %v, %v",
+					invoice.Subtotal, invoice.Tax, invoice.Total, test.subtotal, test.tax, test.total)
+			}
+		})
+	}
+}
+
+func TestCanMove(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{Draft, Sent, true},
+		{Sent, Paid, true},
+		{Draft, Paid, false},
+		{Sent, Draft, false},
+		{Paid, Sent, false},
+		{Paid, Draft, false},
+		{Sent, Sent, false},
+	}
+
+	for _, test := range tests {
+		if got := CanMove(test.from, test.to); got != test.want {
+			t.Errorf("CanMove(%q, %q) = %v, want %v", test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   string
+	}{
+		{0, "0.00"},
+		{5, "5.00"},
+		{999.999, "1,000.00"},
+		{1234.5, "1,234.50"},
+		{1234567.891, "1,234,567.89"},
+		{100000, "100,000.00"},
+		{-1234.5, "-1,234.50"},
+		{-0.001, "0.00"},
+	}
+
+	for _, test := range tests {
+		if got := money(test.amount); got != test.want {
+			t.Errorf("money(%v) = %q, want %q", test.amount, got, test.want)
+		}
+	}
+}
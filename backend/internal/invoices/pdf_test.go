@@ -0,0 +1,126 @@
+package invoices
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Invoice 2024-0001", "Invoice 2024-0001"},
+		{"(draft)", `\(draft\)`},
+		{`C:\invoices`, `C:\\invoices`},
+		{"Müller GmbH", `M\374ller GmbH`},
+		{"1.234,50 €", `1.234,50 \200`},
+		{"“quoted” – dash", `\223quoted\224 \226 dash`},
+		{"tab\there", "tab?here"},
+		{"日本", "??"},
+	}
+
+	for _, test := range tests {
+		if got := pdfString(test.text); got != test.want {
+			t.Errorf("pdfString(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	many := make([]string, 2*linesPerPage+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("line %d", i)
+	}
+
+	tests := []struct {
+		name  string
+		title string
+		lines []string
+		pages int
+	}{
+		{"empty", "Invoice", nil, 1},
+		{"one page", "Invoice (draft)", []string{"ACME", "Total  1,234.50 €"}, 1},
+		{"full page", "Invoice", many[:linesPerPage], 1},
+		{"several pages", "Invoice 2024-0001", many, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := writePDF(test.title, test.lines)
+			objects := checkPDF(t, document)
+
+			if want := 4 + 2*test.pages; objects != want {
+				t.Errorf("objects = %d, want %d", objects, want)
+			}
+			if count := fmt.Sprintf("/Count %d", test.pages); !bytes.Contains(document, []byte(count)) {
+				t.Errorf("page tree lacks %q", count)
+			}
+			for _, line := range test.lines {
+				if !bytes.Contains(document, []byte("("+pdfString(line)+") '")) {
+					t.Errorf("line %q missing", line)
+				}
+			}
+		})
+	}
+}
+
+var (
+	startxrefPattern = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+	streamPattern    = regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)\nendstream`)
+)
+
+// checkPDF checks that document parses the way a reader would: startxref
+// points at the xref table, every entry of the table at its object, and
+// every stream is as long as it says. It returns the number of objects.
+func checkPDF(t *testing.T, document []byte) int {
+	t.Helper()
+
+	if !bytes.HasPrefix(document, []byte("%PDF-1.4\n")) {
+		t.Fatalf("missing header: %q", document[:min(len(document), 16)])
+	}
+
+	match := startxrefPattern.FindSubmatch(document)
+	if match == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(document[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d doesn't point at the xref table", xref)
+	}
+
+	table := strings.Split(string(document[xref:]), "\n")
+	var first, size int
+	if _, err := fmt.Sscanf(table[1], "%d %d", &first, &size); err != nil || first != 0 {
+		t.Fatalf("invalid xref subsection %q", table[1])
+	}
+	if table[2] != "0000000000 65535 f " {
+		t.Errorf("xref entry 0 = %q", table[2])
+	}
+	for i := 1; i < size; i++ {
+		entry := table[2+i]
+		if len(entry) != 19 || !strings.HasSuffix(entry, " 00000 n ") {
+			t.Fatalf("xref entry %d = %q", i, entry)
+		}
+		offset, _ := strconv.Atoi(entry[:10])
+		if header := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(document[offset:], []byte(header)) {
+			t.Errorf("xref entry %d points at %q, want %q", i, document[offset:offset+len(header)], header)
+		}
+	}
+	if trailer := fmt.Sprintf("trailer\n<< /Size %d ", size); !bytes.Contains(document, []byte(trailer)) {
+		t.Errorf("trailer doesn't give /Size %d", size)
+	}
+
+	for _, stream := range streamPattern.FindAllSubmatch(document, -1) {
+		length, _ := strconv.Atoi(string(stream[1]))
+		if length != len(stream[2]) {
+			t.Errorf("stream /Length %d, but it is %d bytes", length, len(stream[2]))
+		}
+	}
+
+	return size - 1
+}
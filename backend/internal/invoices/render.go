@@ -0,0 +1,153 @@
+package invoices
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+)
+
+//go:embed templates
+var builtin embed.FS
+
+// Renderer turns invoices into HTML and PDF. invoice.html is the HTML page
+// and invoice.txt the text of the PDF, laid out in a fixed-width font; a
+// file of the same name in the templates directory replaces the built-in
+// one.
+type Renderer struct {
+	issuer string
+	html   *htmltemplate.Template
+	text   *texttemplate.Template
+}
+
+// view is what the templates see: the invoice and who issues it.
+type view struct {
+	Invoice
+	Issuer string
+}
+
+// NewRenderer parses the templates, preferring those in dir when it is set.
+// issuer is printed as the sender, one line per "\n".
+func NewRenderer(dir, issuer string) (*Renderer, error) {
+	htmlSource, err := readTemplate(dir, "invoice.html")
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("invoice.html").Funcs(templateFuncs).Parse(htmlSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse invoice.html: %w", err)
+	}
+
+	textSource, err := readTemplate(dir, "invoice.txt")
+	if err != nil {
+		return nil, err
+	}
+	text, err := texttemplate.New("invoice.txt").Funcs(templateFuncs).Parse(textSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse invoice.txt: %w", err)
+	}
+
+	return &Renderer{issuer: issuer, html: html, text: text}, nil
+}
+
+// HTML writes the invoice as an HTML page.
+func (r *Renderer) HTML(w io.Writer, invoice Invoice) error {
+	return r.html.Execute(w, view{Invoice: invoice, Issuer: r.issuer})
+}
+
+// PDF writes the invoice as a PDF document.
+func (r *Renderer) PDF(w io.Writer, invoice Invoice) error {
+	var text bytes.Buffer
+	if err := r.text.Execute(&text, view{Invoice: invoice, Issuer: r.issuer}); err != nil {
+		return err
+	}
+
+	title := "Invoice"
+	if invoice.Number != "" {
+		title += " " + invoice.Number
+	}
+	_, err := w.Write(writePDF(title, strings.Split(strings.TrimRight(text.String(), "\n"), "\n")))
+	return err
+}
+
+func readTemplate(dir, name string) (string, error) {
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(data), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+	}
+
+	data, err := builtin.ReadFile("templates/" + name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read built-in %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+var templateFuncs = map[string]interface{}{
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"money": func(amount float64) string {
+		return money(amount)
+	},
+	"hours": func(hours float64) string {
+		return strconv.FormatFloat(hours, 'f', 2, 64)
+	},
+	"percent": func(rate float64) string {
+		return strconv.FormatFloat(rate, 'f', -1, 64) + "%"
+	},
+	"lines": func(text string) []string {
+		if text == "" {
+			return nil
+		}
+		return strings.Split(text, "\n")
+	},
+	"pad": func(width int, text string) string {
+		text = truncate(text, width)
+		return text + strings.Repeat(" ", width-utf8.RuneCountInString(text))
+	},
+	"lpad": func(width int, text string) string {
+		text = truncate(text, width)
+		return strings.Repeat(" ", width-utf8.RuneCountInString(text)) + text
+	},
+	"repeat": func(count int, text string) string {
+		return strings.Repeat(text, count)
+	},
+}
+
+// money formats an amount with two decimals and thousands separators.
+func money(amount float64) string {
+	digits := strconv.FormatFloat(math.Abs(amount), 'f', 2, 64)
+	sign := ""
+	if amount < 0 && digits != "0.00" {
+		sign = "-"
+	}
+
+	whole, cents := digits[:len(digits)-3], digits[len(digits)-3:]
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	return sign + whole + cents
+}
+
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,218 @@
+package live
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/types"
+	"sync"
+	"time"
+)
+
+// Event is one server-sent event.
+type Event struct {
+	Name string
+	Data interface{}
+}
+
+// Poller polls the trackers on behalf of every connected client. Clients
+// are grouped by timezone, since day and month totals depend on it, and it
+// only polls while someone is listening.
+type Poller struct {
+	trackersRepo *repositories.TrackersRepository
+	interval     time.Duration
+	holidays     *repositories.HolidaysRepository
+	logger       *slog.Logger
+
+	mu     sync.Mutex
+	groups map[string]*group
+}
+
+type group struct {
+	location    *time.Location
+	subscribers map[chan Event]struct{}
+	latest      []Event
+
+	// last totals seen, to detect goals being crossed
+	day, month           string
+	dayHours, monthHours float64
+}
+
+func NewPoller(trackersRepo *repositories.TrackersRepository, holidays *repositories.HolidaysRepository, interval time.Duration, logger *slog.Logger) *Poller {
+	return &Poller{
+		trackersRepo: trackersRepo,
+		interval:     interval,
+		holidays:     holidays,
+		logger:       logger.With("component", "live"),
+		groups:       make(map[string]*group),
+	}
+}
+
+// Subscribe returns a channel of events for loc, starting with the latest
+// known state, and a function to stop listening.
+func (p *Poller) Subscribe(loc *time.Location) (<-chan Event, func()) {
+	events := make(chan Event, 16)
+
+	p.mu.Lock()
+	g, ok := p.groups[loc.String()]
+	if !ok {
+		g = &group{location: loc, subscribers: make(map[chan Event]struct{})}
+		p.groups[loc.String()] = g
+	}
+	g.subscribers[events] = struct{}{}
+	for _, event := range g.latest {
+		events <- event
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		// Don't make a new timezone wait a whole interval
+		go p.poll()
+	}
+
+	return events, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		delete(g.subscribers, events)
+		if len(g.subscribers) == 0 {
+			delete(p.groups, loc.String())
+		}
+	}
+}
+
+// Run polls every interval until ctx is cancelled.
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.poll()
+		}
+	}
+}
+
+func (p *Poller) poll() {
+	p.mu.Lock()
+	locations := make([]*time.Location, 0, len(p.groups))
+	for _, g := range p.groups {
+		locations = append(locations, g.location)
+	}
+	p.mu.Unlock()
+
+	if len(locations) == 0 {
+		return
+	}
+
+	now := time.Now()
+	timers := p.trackersRepo.RunningTimers()
+
+	runningSeconds := 0
+	timerMaps := make([]map[string]interface{}, len(timers))
+	for i, timer := range timers {
+		runningSeconds += timer.Elapsed(now)
+		timerMaps[i] = timer.ToMap(now)
+	}
+	runningHours := float64(runningSeconds) / 3600
+
+	for _, loc := range locations {
+		snapshots, err := p.trackersRepo.MonthSnapshots(now.In(loc))
+		if err != nil {
+			p.logger.Warn("failed to get month snapshots", "location", loc.String(), "error", err)
+			continue
+		}
+
+		// Goals follow the schedule profile in effect, like the day view
+		local := now.In(loc)
+		som := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+		calendar, err := p.holidays.Calendar(som, som.AddDate(0, 1, 0))
+		if err != nil {
+			p.logger.Warn("failed to get calendar", "location", loc.String(), "error", err)
+			continue
+		}
+		dailyGoal := calendar.Target(local)
+		monthlyGoal := calendar.MonthlyGoal(som)
+
+		p.publish(loc, local, timerMaps, runningHours, snapshots, dailyGoal, monthlyGoal)
+	}
+}
+
+func (p *Poller) publish(loc *time.Location, now time.Time, timers []map[string]interface{}, runningHours float64, snapshots types.MonthSnapshots, dailyGoal, monthlyGoal float64) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	som := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	tomorrow := day.AddDate(0, 0, 1)
+
+	// Like the day view, hours is what the trackers recorded and running
+	// timers are added to month_hours only. Each goal is reached by the
+	// total it is shown against, so a daily goal event never arrives while
+	// hours is still below it
+	dayHours := snapshots.Hours(day, tomorrow)
+	monthHours := snapshots.Hours(som, tomorrow) + runningHours
+
+	events := []Event{
+		{Name: "timers", Data: map[string]interface{}{"timers": timers}},
+		{Name: "totals", Data: map[string]interface{}{
+			"date":          day.Format("2006-01-02"),
+			"hours":         round(dayHours),
+			"month_hours":   round(monthHours),
+			"running_hours": round(runningHours),
+			"daily_goal":    dailyGoal,
+			"monthly_goal":  round(monthlyGoal),
+			"stale":         snapshots.Stale(now),
+		}},
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	g, ok := p.groups[loc.String()]
+	if !ok {
+		return
+	}
+
+	dayKey, monthKey := day.Format("2006-01-02"), som.Format("2006-01")
+	var goals []Event
+	if g.day == dayKey && crossed(g.dayHours, dayHours, dailyGoal) {
+		goals = append(goals, goalEvent("daily", dayKey, dailyGoal, dayHours))
+	}
+	if g.month == monthKey && crossed(g.monthHours, monthHours, monthlyGoal) {
+		goals = append(goals, goalEvent("monthly", monthKey, round(monthlyGoal), monthHours))
+	}
+	g.day, g.dayHours = dayKey, dayHours
+	g.month, g.monthHours = monthKey, monthHours
+	g.latest = events
+
+	broadcast := append(append([]Event{}, events...), goals...)
+	for subscriber := range g.subscribers {
+		for _, event := range broadcast {
+			select {
+			case subscriber <- event:
+			default:
+				// a slow client misses this round rather than blocking
+				// everyone else
+			}
+		}
+	}
+}
+
+func crossed(before, after, goal float64) bool {
+	return goal > 0 && before < goal && after >= goal
+}
+
+func goalEvent(goal, period string, target, hours float64) Event {
+	return Event{Name: "goal", Data: map[string]interface{}{
+		"goal":   goal,
+		"period": period,
+		"target": target,
+		"hours":  round(hours),
+	}}
+}
+
+func round(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
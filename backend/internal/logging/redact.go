@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values are never logged, matched
+// case-insensitively. Header groups built with Headers use the header name
+// as the key, so credentials sent as headers are covered too.
+var sensitiveKeys = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+	"api-key":             true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"token":               true,
+	"password":            true,
+	"secret":              true,
+}
+
+// RedactingHandler masks sensitive attributes and known secret values
+// before passing records on to the wrapped handler.
+type RedactingHandler struct {
+	next    slog.Handler
+	secrets []string
+}
+
+func NewRedactingHandler(next slog.Handler, secrets []string) *RedactingHandler {
+	filtered := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			filtered = append(filtered, secret)
+		}
+	}
+
+	return &RedactingHandler{
+		next:    next,
+		secrets: filtered,
+	}
+}
+
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	clean := slog.NewRecord(record.Time, record.Level, h.redactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		clean.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+
+	return h.next.Handle(ctx, clean)
+}
+
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		clean[i] = h.redactAttr(attr)
+	}
+
+	return &RedactingHandler{next: h.next.WithAttrs(clean), secrets: h.secrets}
+}
+
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{next: h.next.WithGroup(name), secrets: h.secrets}
+}
+
+func (h *RedactingHandler) redactAttr(attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redacted)
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		clean := make([]any, len(group))
+		for i, item := range group {
+			clean[i] = h.redactAttr(item)
+		}
+		return slog.Group(attr.Key, clean...)
+	case slog.KindString:
+		return slog.String(attr.Key, h.redactString(value.String()))
+	case slog.KindAny:
+		// Errors and Stringers are logged as their text; anything else is
+		// only replaced by its text when that contains a secret
+		switch v := value.Any().(type) {
+		case error:
+			return slog.String(attr.Key, h.redactString(v.Error()))
+		case fmt.Stringer:
+			return slog.String(attr.Key, h.redactString(v.String()))
+		default:
+			text := fmt.Sprintf("%+v", v)
+			if clean := h.redactString(text); clean != text {
+				return slog.String(attr.Key, clean)
+			}
+			return attr
+		}
+	default:
+		return attr
+	}
+}
+
+func (h *RedactingHandler) redactString(s string) string {
+	for _, secret := range h.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// Headers turns HTTP headers into a log group so that credential headers
+// are caught by key.
+func Headers(headers http.Header) slog.Attr {
+	attrs := make([]any, 0, len(headers))
+	for key := range headers {
+		attrs = append(attrs, slog.String(key, headers.Get(key)))
+	}
+	return slog.Group("headers", attrs...)
+}
@@ -0,0 +1,99 @@
+package periods
+
+import (
+	"errors"
+	"time"
+)
+
+// Kinds of billing period
+const (
+	// Monthly periods run from StartDay of one month to the day before
+	// StartDay of the next, e.g. the 26th to the 25th
+	Monthly = "monthly"
+	// Cycle periods run for Weeks weeks at a time, counted from Anchor
+	Cycle = "cycle"
+)
+
+// Definition describes how a client's billing periods are laid out.
+type Definition struct {
+	ID   uint
+	Name string
+	Kind string
+	// StartDay is the day of the month Monthly periods start on, 1-28 so
+	// every month has it
+	StartDay int
+	// Weeks is the length of Cycle periods and Anchor (midnight UTC of the
+	// calendar day) the start of any one of them
+	Weeks  int
+	Anchor time.Time
+}
+
+// Period is the half-open range [From, To) of calendar days.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Validate reports what is wrong with the definition, if anything.
+func (d Definition) Validate() error {
+	switch d.Kind {
+	case Monthly:
+		if d.StartDay < 1 || d.StartDay > 28 {
+			return errors.New("start_day must be between 1 and 28")
+		}
+	case Cycle:
+		if d.Weeks < 1 || d.Weeks > 52 {
+			return errors.New("weeks must be between 1 and 52")
+		}
+		if d.Anchor.IsZero() {
+			return errors.New("a cycle needs an anchor date")
+		}
+	default:
+		return errors.New("kind must be monthly or cycle")
+	}
+	return nil
+}
+
+// Containing returns the period day falls in. Periods start at midnight in
+// day's timezone.
+func (d Definition) Containing(day time.Time) Period {
+	loc := day.Location()
+
+	if d.Kind == Cycle {
+		anchor := time.Date(d.Anchor.Year(), d.Anchor.Month(), d.Anchor.Day(), 0, 0, 0, 0, loc)
+		// Count calendar days in UTC, where none are shorter or longer
+		days := int(dateOf(day).Sub(dateOf(anchor)).Hours() / 24)
+		length := d.Weeks * 7
+		offset := days % length
+		if offset < 0 {
+			offset += length
+		}
+		from := time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, loc)
+		return Period{From: from, To: from.AddDate(0, 0, length)}
+	}
+
+	from := time.Date(day.Year(), day.Month(), d.StartDay, 0, 0, 0, 0, loc)
+	if day.Day() < d.StartDay {
+		from = from.AddDate(0, -1, 0)
+	}
+	return Period{From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// Previous returns the period before p.
+func (d Definition) Previous(p Period) Period {
+	return d.Containing(p.From.AddDate(0, 0, -1))
+}
+
+// Next returns the period after p.
+func (d Definition) Next(p Period) Period {
+	return d.Containing(p.To)
+}
+
+// Last is the last day of the period.
+func (p Period) Last() time.Time {
+	return p.To.AddDate(0, 0, -1)
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
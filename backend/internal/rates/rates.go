@@ -0,0 +1,175 @@
+package rates
+
+import (
+	"myspace/backend/internal/types"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Rate is an hourly rate for a project, or for every project of a source
+// when ProjectID is empty, from EffectiveFrom (midnight UTC of the calendar
+// day) until the next rate for the same project or source starts.
+type Rate struct {
+	ID            uint
+	Source        string
+	ProjectID     string
+	Hourly        float64
+	Currency      string
+	EffectiveFrom time.Time
+}
+
+// Table finds the rate in effect for an entry.
+type Table struct {
+	rates map[string][]Rate
+}
+
+// New builds a table from rates in any order.
+func New(rates []Rate) *Table {
+	t := &Table{rates: make(map[string][]Rate)}
+	for _, rate := range rates {
+		key := rate.Source + ":" + rate.ProjectID
+		t.rates[key] = append(t.rates[key], rate)
+	}
+	for _, list := range t.rates {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].EffectiveFrom.Before(list[j].EffectiveFrom)
+		})
+	}
+	return t
+}
+
+// Lookup returns the rate in effect on day for a project: its own, or
+// else its source's.
+func (t *Table) Lookup(source, projectID string, day time.Time) (Rate, bool) {
+	if projectID != "" {
+		if rate, ok := t.latest(source+":"+projectID, day); ok {
+			return rate, true
+		}
+	}
+	return t.latest(source+":", day)
+}
+
+func (t *Table) latest(key string, day time.Time) (Rate, bool) {
+	date := day.Format("2006-01-02")
+	list := t.rates[key]
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].EffectiveFrom.Format("2006-01-02") <= date {
+			return list[i], true
+		}
+	}
+	return Rate{}, false
+}
+
+// Earnings is what a set of entries earned. Amounts are kept per currency
+// since rates may be in different ones.
+type Earnings struct {
+	// Projects holds the amounts per "source:project_id"
+	Projects map[string]map[string]float64
+	Totals   map[string]float64
+	// Lines split the hours of each project by the rate they were billed
+	// at, in the order first seen
+	Lines []Line
+	// UnratedHours are the hours no rate applied to, and Unrated those
+	// hours per "source:project_id"
+	UnratedHours float64
+	Unrated      map[string]float64
+
+	lines map[string]int
+}
+
+// Line is the hours of a project billed at one rate.
+type Line struct {
+	Source    string
+	ProjectID string
+	Hours     float64
+	Hourly    float64
+	Currency  string
+}
+
+// Price adds up what entries earned, each day of an entry at the rate in
+// effect on that day in loc. Entries without a start are priced at the
+// rates of last.
+//
+// Sources whose entries carry no project, like Mayven's, are priced from
+// projects, their per-project breakdown of the month or of the range, at
+// the rates of last. That is scaled down to the share of the breakdown's
+// hours the entries cover, so a single day gets its part of the month's
+// amount.
+func (t *Table) Price(entries, projects types.ProjectTimeList, loc *time.Location, last time.Time) Earnings {
+	earnings := Earnings{
+		Projects: make(map[string]map[string]float64),
+		Totals:   make(map[string]float64),
+		Unrated:  make(map[string]float64),
+		lines:    make(map[string]int),
+	}
+
+	withProjects := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.ProjectID != "" {
+			withProjects[entry.Source] = true
+		}
+	}
+
+	entrySeconds := make(map[string]int)
+	for _, entry := range entries {
+		if !withProjects[entry.Source] {
+			entrySeconds[entry.Source] += entry.Seconds
+			continue
+		}
+		for _, part := range entry.SplitByDay(loc) {
+			day := last
+			if part.Datetime != nil {
+				day = part.Datetime.In(loc)
+			}
+			earnings.add(t, part, day, 1)
+		}
+	}
+
+	projectSeconds := make(map[string]int)
+	for _, project := range projects {
+		projectSeconds[project.Source] += project.Seconds
+	}
+	for _, project := range projects {
+		if withProjects[project.Source] || entrySeconds[project.Source] == 0 || projectSeconds[project.Source] == 0 {
+			continue
+		}
+		share := float64(entrySeconds[project.Source]) / float64(projectSeconds[project.Source])
+		earnings.add(t, project, last, share)
+	}
+
+	return earnings
+}
+
+// add prices share of entry's hours at the rate of day.
+func (e *Earnings) add(t *Table, entry types.ProjectTime, day time.Time, share float64) {
+	hours := float64(entry.Seconds) / 3600 * share
+
+	key := entry.Source + ":" + entry.ProjectID
+	rate, ok := t.Lookup(entry.Source, entry.ProjectID, day)
+	if !ok {
+		e.UnratedHours += hours
+		e.Unrated[key] += hours
+		return
+	}
+
+	if e.Projects[key] == nil {
+		e.Projects[key] = make(map[string]float64)
+	}
+	e.Projects[key][rate.Currency] += hours * rate.Hourly
+	e.Totals[rate.Currency] += hours * rate.Hourly
+
+	lineKey := key + ":" + strconv.FormatFloat(rate.Hourly, 'f', -1, 64) + ":" + rate.Currency
+	i, ok := e.lines[lineKey]
+	if !ok {
+		i = len(e.Lines)
+		e.lines[lineKey] = i
+		e.Lines = append(e.Lines, Line{
+			Source:    entry.Source,
+			ProjectID: entry.ProjectID,
+			Hourly:    rate.Hourly,
+			Currency:  rate.Currency,
+		})
+	}
+	e.Lines[i].Hours += hours
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"fmt"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/workdays"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HolidaysRepository combines the imported holidays with the built-in ones
+// of the preferred country, the leave taken and the schedule profiles into
+// working-day calendars.
+type HolidaysRepository struct {
+	db          *gorm.DB
+	preferences *PreferencesRepository
+	leave       *LeaveRepository
+	schedules   *SchedulesRepository
+}
+
+func NewHolidaysRepository(db *gorm.DB, preferences *PreferencesRepository, leave *LeaveRepository, schedules *SchedulesRepository) *HolidaysRepository {
+	return &HolidaysRepository{
+		db:          db,
+		preferences: preferences,
+		leave:       leave,
+		schedules:   schedules,
+	}
+}
+
+// Calendar returns the working-day calendar covering [from, to).
+func (r *HolidaysRepository) Calendar(from, to time.Time) (*workdays.Calendar, error) {
+	prefs, err := r.preferences.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := r.Between(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	leave, err := r.leave.Between(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := r.schedules.All()
+	if err != nil {
+		return nil, err
+	}
+
+	goals := workdays.Goals{Daily: prefs.DailyGoal, Monthly: prefs.MonthlyGoal}
+	return workdays.New(prefs.WorkWeekdays(), goals, holidays, leave, profiles), nil
+}
+
+// Year returns the holidays of a year, built-in and imported.
+func (r *HolidaysRepository) Year(year int) ([]workdays.Holiday, error) {
+	return r.Between(
+		time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+	)
+}
+
+// Between returns the holidays on the calendar days in [from, to), sorted by
+// date. An imported holiday replaces a built-in one on the same day.
+func (r *HolidaysRepository) Between(from, to time.Time) ([]workdays.Holiday, error) {
+	prefs, err := r.preferences.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	first, end := from.Format("2006-01-02"), to.Format("2006-01-02")
+	byDate := make(map[string]workdays.Holiday)
+
+	if prefs.HolidayCountry != "" {
+		for year := from.Year(); year <= to.Year(); year++ {
+			for _, holiday := range workdays.PublicHolidays(prefs.HolidayCountry, year) {
+				byDate[holiday.Date.Format("2006-01-02")] = holiday
+			}
+		}
+	}
+
+	var rows []database.Holiday
+	if err := r.db.Where("date >= ? AND date < ?", first, end).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	for _, row := range rows {
+		date, err := time.Parse("2006-01-02", row.Date)
+		if err != nil {
+			continue
+		}
+		byDate[row.Date] = workdays.Holiday{Date: date, Name: row.Name, Source: "imported"}
+	}
+
+	var holidays []workdays.Holiday
+	for date, holiday := range byDate {
+		if date >= first && date < end {
+			holidays = append(holidays, holiday)
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+
+	return holidays, nil
+}
+
+// Import stores holidays, replacing the names of days already imported.
+func (r *HolidaysRepository) Import(holidays []workdays.Holiday) error {
+	if len(holidays) == 0 {
+		return nil
+	}
+
+	// A day listed twice keeps its last name; one statement can't upsert a
+	// row twice.
+	index := make(map[string]int)
+	var rows []database.Holiday
+	for _, holiday := range holidays {
+		date := holiday.Date.Format("2006-01-02")
+		if i, ok := index[date]; ok {
+			rows[i].Name = holiday.Name
+			continue
+		}
+		index[date] = len(rows)
+		rows = append(rows, database.Holiday{Date: date, Name: holiday.Name})
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).Create(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to import holidays: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the imported holiday on date; ok is false when there is
+// none. Built-in holidays can't be deleted.
+func (r *HolidaysRepository) Delete(date string) (bool, error) {
+	result := r.db.Where("date = ?", date).Delete(&database.Holiday{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete holiday %s: %w", date, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, touchDeleted(r.db)
+}
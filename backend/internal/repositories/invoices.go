@@ -0,0 +1,293 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/invoices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvoiceIssued is returned when deleting an invoice that was sent.
+	ErrInvoiceIssued = errors.New("only draft invoices can be deleted")
+	// ErrInvoiceStatus is returned for a move the invoice can't make.
+	ErrInvoiceStatus = errors.New("invoices only move from draft to sent to paid")
+)
+
+// InvoicesRepository stores invoices with their lines.
+type InvoicesRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoicesRepository(db *gorm.DB) *InvoicesRepository {
+	return &InvoicesRepository{
+		db: db,
+	}
+}
+
+// All returns the invoices in status, or all of them when it is empty,
+// newest first.
+func (r *InvoicesRepository) All(status string) ([]invoices.Invoice, error) {
+	query := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Order("id DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var rows []database.Invoice
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get invoices: %w", err)
+	}
+
+	list := make([]invoices.Invoice, 0, len(rows))
+	for _, row := range rows {
+		invoice, err := invoiceFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, invoice)
+	}
+	return list, nil
+}
+
+// Get returns the invoice with id; ok is false when there is none.
+func (r *InvoicesRepository) Get(id uint) (invoices.Invoice, bool, error) {
+	return r.get(r.db, id)
+}
+
+func (r *InvoicesRepository) get(db *gorm.DB, id uint) (invoices.Invoice, bool, error) {
+	var rows []database.Invoice
+	err := db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Where("id = ?", id).Limit(1).Find(&rows).Error
+	if err != nil {
+		return invoices.Invoice{}, false, fmt.Errorf("failed to get invoice %d: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return invoices.Invoice{}, false, nil
+	}
+
+	invoice, err := invoiceFromRow(rows[0])
+	return invoice, err == nil, err
+}
+
+// Create stores invoice as a draft with its lines and sets its ID.
+func (r *InvoicesRepository) Create(invoice *invoices.Invoice) error {
+	invoice.Status = invoices.Draft
+	row := invoiceToRow(*invoice)
+
+	if err := r.db.Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	invoice.ID = row.ID
+	invoice.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// Move changes the status of the invoice with id as of today. Sending it
+// gives it the next number of today's year and a due date dueDays later;
+// paying it records the day. ok is false when there is no such invoice.
+func (r *InvoicesRepository) Move(id uint, status string, today time.Time, dueDays int) (invoices.Invoice, bool, error) {
+	var invoice invoices.Invoice
+	var found bool
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		invoice, found, err = r.get(tx, id)
+		if err != nil || !found {
+			return err
+		}
+		if !invoices.CanMove(invoice.Status, status) {
+			return ErrInvoiceStatus
+		}
+
+		updates := map[string]interface{}{"status": status}
+		switch status {
+		case invoices.Sent:
+			number, err := nextInvoiceNumber(tx, today.Year())
+			if err != nil {
+				return err
+			}
+			updates["number"] = number
+			updates["issued_on"] = today.Format("2006-01-02")
+			updates["due_on"] = today.AddDate(0, 0, dueDays).Format("2006-01-02")
+		case invoices.Paid:
+			updates["paid_on"] = today.Format("2006-01-02")
+		}
+
+		if err := tx.Model(&database.Invoice{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update invoice %d: %w", id, err)
+		}
+		invoice, _, err = r.get(tx, id)
+		return err
+	})
+	return invoice, found, err
+}
+
+// Delete removes the draft invoice with id; ok is false when there is none.
+func (r *InvoicesRepository) Delete(id uint) (bool, error) {
+	var found bool
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var rows []database.Invoice
+		if err := tx.Where("id = ?", id).Limit(1).Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to get invoice %d: %w", id, err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		found = true
+		if rows[0].Status != invoices.Draft {
+			return ErrInvoiceIssued
+		}
+
+		if err := tx.Where("invoice_id = ?", id).Delete(&database.InvoiceLine{}).Error; err != nil {
+			return fmt.Errorf("failed to delete invoice lines %d: %w", id, err)
+		}
+		if err := tx.Delete(&database.Invoice{}, id).Error; err != nil {
+			return fmt.Errorf("failed to delete invoice %d: %w", id, err)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// nextInvoiceNumber continues the numbering of year, e.g. 2024-0007 after
+// 2024-0006. Numbers are never reused since only drafts, which have none,
+// can be deleted. They are ordered by their sequence as a number, since
+// past 9999 it outgrows the padding and 2024-10000 sorts before 2024-9999
+// as text.
+func nextInvoiceNumber(tx *gorm.DB, year int) (string, error) {
+	prefix := strconv.Itoa(year) + "-"
+
+	var numbers []string
+	err := tx.Model(&database.Invoice{}).Where("number LIKE ?", prefix+"%").
+		Order(fmt.Sprintf("CAST(substr(number, %d) AS INTEGER) DESC", len(prefix)+1)).
+		Limit(1).Pluck("number", &numbers).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to get the last invoice number: %w", err)
+	}
+
+	next := 1
+	if len(numbers) > 0 {
+		last, err := strconv.Atoi(strings.TrimPrefix(numbers[0], prefix))
+		if err != nil {
+			return "", fmt.Errorf("invalid invoice number %q: %w", numbers[0], err)
+		}
+		next = last + 1
+	}
+	return fmt.Sprintf("%s%04d", prefix, next), nil
+}
+
+func invoiceToRow(invoice invoices.Invoice) database.Invoice {
+	row := database.Invoice{
+		ID:            invoice.ID,
+		Status:        invoice.Status,
+		Client:        invoice.Client,
+		ClientAddress: invoice.ClientAddress,
+		Sources:       strings.Join(invoice.Sources, ","),
+		Projects:      strings.Join(invoice.Projects, ","),
+		PeriodFrom:    invoice.From.Format("2006-01-02"),
+		PeriodTo:      invoice.To.Format("2006-01-02"),
+		Currency:      invoice.Currency,
+		Subtotal:      invoice.Subtotal,
+		TaxRate:       invoice.TaxRate,
+		Tax:           invoice.Tax,
+		Total:         invoice.Total,
+		Note:          invoice.Note,
+		IssuedOn:      formatDay(invoice.IssuedOn),
+		DueOn:         formatDay(invoice.DueOn),
+		PaidOn:        formatDay(invoice.PaidOn),
+	}
+	if invoice.Number != "" {
+		row.Number = &invoice.Number
+	}
+	for i, line := range invoice.Lines {
+		row.Lines = append(row.Lines, database.InvoiceLine{
+			Position:    i,
+			Source:      line.Source,
+			ProjectID:   line.ProjectID,
+			Description: line.Description,
+			Hours:       line.Hours,
+			Rate:        line.Rate,
+			Amount:      line.Amount,
+		})
+	}
+	return row
+}
+
+func invoiceFromRow(row database.Invoice) (invoices.Invoice, error) {
+	invoice := invoices.Invoice{
+		ID:            row.ID,
+		Status:        row.Status,
+		Client:        row.Client,
+		ClientAddress: row.ClientAddress,
+		Sources:       splitColumn(row.Sources),
+		Projects:      splitColumn(row.Projects),
+		Currency:      row.Currency,
+		Subtotal:      row.Subtotal,
+		TaxRate:       row.TaxRate,
+		Tax:           row.Tax,
+		Total:         row.Total,
+		Note:          row.Note,
+		CreatedAt:     row.CreatedAt,
+	}
+	if row.Number != nil {
+		invoice.Number = *row.Number
+	}
+
+	days := []struct {
+		target *time.Time
+		value  string
+	}{
+		{&invoice.From, row.PeriodFrom},
+		{&invoice.To, row.PeriodTo},
+		{&invoice.IssuedOn, row.IssuedOn},
+		{&invoice.DueOn, row.DueOn},
+		{&invoice.PaidOn, row.PaidOn},
+	}
+	for _, day := range days {
+		if day.value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", day.value)
+		if err != nil {
+			return invoices.Invoice{}, fmt.Errorf("invalid date %q on invoice %d: %w", day.value, row.ID, err)
+		}
+		*day.target = parsed
+	}
+
+	for _, line := range row.Lines {
+		invoice.Lines = append(invoice.Lines, invoices.Line{
+			Source:      line.Source,
+			ProjectID:   line.ProjectID,
+			Description: line.Description,
+			Hours:       line.Hours,
+			Rate:        line.Rate,
+			Amount:      line.Amount,
+		})
+	}
+	return invoice, nil
+}
+
+func formatDay(day time.Time) string {
+	if day.IsZero() {
+		return ""
+	}
+	return day.Format("2006-01-02")
+}
+
+// splitColumn reads a list stored comma-separated, empty for none.
+func splitColumn(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
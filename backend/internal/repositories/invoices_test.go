@@ -0,0 +1,247 @@
+package repositories
+
+import (
+	"errors"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/invoices"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newInvoicesRepository(t *testing.T) *InvoicesRepository {
+	t.Helper()
+	return NewInvoicesRepository(newDB(t))
+}
+
+// newDB opens a migrated database in a temporary file.
+func newDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := database.Connect(filepath.Join(t.TempDir(), "test.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func draft(t *testing.T, repo *InvoicesRepository) uint {
+	t.Helper()
+
+	invoice := invoices.Invoice{
+		Client:   "ACME",
+		Sources:  []string{"clockify"},
+		Projects: []string{"mayven:42"},
+		From:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		Currency: "EUR",
+		Lines: []invoices.Line{
+			{Source: "clockify", ProjectID: "web", Description: "Website", Hours: 2, Rate: 50, Amount: 100},
+		},
+		Subtotal: 100,
+		Total:    100,
+	}
+	if err := repo.Create(&invoice); err != nil {
+		t.Fatal(err)
+	}
+	return invoice.ID
+}
+
+func day(year int, month time.Month, dayOfMonth int) time.Time {
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+}
+
+func TestInvoicesNumbering(t *testing.T) {
+	repo := newInvoicesRepository(t)
+
+	sends := []struct {
+		today time.Time
+		want  string
+	}{
+		{day(2023, 12, 30), "2023-0001"},
+		{day(2023, 12, 31), "2023-0002"},
+		{day(2024, 1, 2), "2024-0001"},
+		{day(2024, 1, 3), "2024-0002"},
+		{day(2023, 12, 31), "2023-0003"},
+		{day(2025, 6, 1), "2025-0001"},
+		{day(2024, 12, 31), "2024-0003"},
+	}
+
+	for _, send := range sends {
+		id := draft(t, repo)
+		invoice, found, err := repo.Move(id, invoices.Sent, send.today, 14)
+		if err != nil || !found {
+			t.Fatalf("Move(%d) = %v, %v", id, found, err)
+		}
+		if invoice.Number != send.want {
+			t.Errorf("sent on %s: number %q, want %q", send.today.Format("2006-01-02"), invoice.Number, send.want)
+		}
+		if !invoice.IssuedOn.Equal(send.today) || !invoice.DueOn.Equal(send.today.AddDate(0, 0, 14)) {
+			t.Errorf("sent on %s: issued %s, due %s", send.today.Format("2006-01-02"), invoice.IssuedOn, invoice.DueOn)
+		}
+	}
+
+	// Deleting a draft leaves no gap, since drafts have no number
+	deleted := draft(t, repo)
+	if _, err := repo.Delete(deleted); err != nil {
+		t.Fatal(err)
+	}
+	invoice, _, err := repo.Move(draft(t, repo), invoices.Sent, day(2024, 2, 1), 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invoice.Number != "2024-0004" {
+		t.Errorf("number after a deleted draft %q, want 2024-0004", invoice.Number)
+	}
+}
+
+func TestInvoicesNumberingPastPadding(t *testing.T) {
+	repo := newInvoicesRepository(t)
+
+	for _, number := range []string{"2024-9998", "2024-9999", "2024-10000"} {
+		id := draft(t, repo)
+		if err := repo.db.Model(&database.Invoice{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"number": number, "status": invoices.Sent}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []string{"2024-10001", "2024-10002"} {
+		invoice, _, err := repo.Move(draft(t, repo), invoices.Sent, day(2024, 5, 1), 14)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if invoice.Number != want {
+			t.Errorf("number %q, want %q", invoice.Number, want)
+		}
+	}
+}
+
+func TestInvoicesMove(t *testing.T) {
+	tests := []struct {
+		name   string
+		moves  []string
+		status string
+		err    error
+	}{
+		{"send", []string{invoices.Sent}, invoices.Sent, nil},
+		{"send and pay", []string{invoices.Sent, invoices.Paid}, invoices.Paid, nil},
+		{"pay a draft", []string{invoices.Paid}, invoices.Draft, ErrInvoiceStatus},
+		{"send twice", []string{invoices.Sent, invoices.Sent}, invoices.Sent, ErrInvoiceStatus},
+		{"pay twice", []string{invoices.Sent, invoices.Paid, invoices.Paid}, invoices.Paid, ErrInvoiceStatus},
+		{"back to draft", []string{invoices.Sent, invoices.Draft}, invoices.Sent, ErrInvoiceStatus},
+		{"paid back to sent", []string{invoices.Sent, invoices.Paid, invoices.Sent}, invoices.Paid, ErrInvoiceStatus},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newInvoicesRepository(t)
+			id := draft(t, repo)
+
+			var err error
+			for i, status := range test.moves {
+				_, _, err = repo.Move(id, status, day(2024, 4, 2+i), 14)
+				if err != nil && i < len(test.moves)-1 {
+					t.Fatalf("move %d to %s: %v", i, status, err)
+				}
+			}
+			if !errors.Is(err, test.err) {
+				t.Errorf("last move error = %v, want %v", err, test.err)
+			}
+
+			invoice, _, err := repo.Get(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if invoice.Status != test.status {
+				t.Errorf("status %q, want %q", invoice.Status, test.status)
+			}
+			if test.status == invoices.Paid && !invoice.PaidOn.Equal(day(2024, 4, 3)) {
+				t.Errorf("paid on %s, want 2024-04-03", invoice.PaidOn)
+			}
+			if test.status == invoices.Draft && invoice.Number != "" {
+				t.Errorf("draft numbered %q", invoice.Number)
+			}
+		})
+	}
+
+	repo := newInvoicesRepository(t)
+	if _, found, err := repo.Move(99, invoices.Sent, day(2024, 4, 2), 14); found || err != nil {
+		t.Errorf("Move(unknown) = %v, %v, want not found", found, err)
+	}
+}
+
+func TestInvoicesDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		moves   []string
+		deleted bool
+		err     error
+	}{
+		{"draft", nil, true, nil},
+		{"sent", []string{invoices.Sent}, false, ErrInvoiceIssued},
+		{"paid", []string{invoices.Sent, invoices.Paid}, false, ErrInvoiceIssued},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newInvoicesRepository(t)
+			id := draft(t, repo)
+			for _, status := range test.moves {
+				if _, _, err := repo.Move(id, status, day(2024, 4, 2), 14); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			found, err := repo.Delete(id)
+			if !found || !errors.Is(err, test.err) {
+				t.Fatalf("Delete() = %v, %v, want found, %v", found, err, test.err)
+			}
+
+			_, exists, err := repo.Get(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists == test.deleted {
+				t.Errorf("invoice exists %v after Delete, want %v", exists, !test.deleted)
+			}
+		})
+	}
+
+	repo := newInvoicesRepository(t)
+	if found, err := repo.Delete(99); found || err != nil {
+		t.Errorf("Delete(unknown) = %v, %v, want not found", found, err)
+	}
+}
+
+func TestInvoicesRoundTrip(t *testing.T) {
+	repo := newInvoicesRepository(t)
+	id := draft(t, repo)
+
+	invoice, found, err := repo.Get(id)
+	if err != nil || !found {
+		t.Fatalf("Get() = %v, %v", found, err)
+	}
+	if len(invoice.Sources) != 1 || invoice.Sources[0] != "clockify" {
+		t.Errorf("sources %q, want [clockify]", invoice.Sources)
+	}
+	if len(invoice.Projects) != 1 || invoice.Projects[0] != "mayven:42" {
+		t.Errorf("projects %q, want [mayven:42]", invoice.Projects)
+	}
+	if !invoice.From.Equal(day(2024, 3, 1)) || !invoice.To.Equal(day(2024, 3, 31)) {
+		t.Errorf("period %s to %s", invoice.From, invoice.To)
+	}
+	if len(invoice.Lines) != 1 || invoice.Lines[0].Amount != 100 || invoice.Status != invoices.Draft {
+		t.Errorf("invoice %+v", invoice)
+	}
+}
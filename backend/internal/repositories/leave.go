@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/workdays"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrLeaveExceedsDay is returned when leave would take more than a whole day.
+var ErrLeaveExceedsDay = errors.New("leave exceeds a full day")
+
+type LeaveRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveRepository(db *gorm.DB) *LeaveRepository {
+	return &LeaveRepository{
+		db: db,
+	}
+}
+
+// Between returns the leave on the calendar days in [from, to), by date.
+func (r *LeaveRepository) Between(from, to time.Time) ([]workdays.Leave, error) {
+	var rows []database.Leave
+	err := r.db.Where("date >= ? AND date < ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date, id").Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leave: %w", err)
+	}
+
+	leave := make([]workdays.Leave, 0, len(rows))
+	for _, row := range rows {
+		entry, err := leaveFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		leave = append(leave, entry)
+	}
+	return leave, nil
+}
+
+// Year returns the leave of a year.
+func (r *LeaveRepository) Year(year int) ([]workdays.Leave, error) {
+	return r.Between(
+		time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+	)
+}
+
+// Get returns the entry with id; ok is false when there is none.
+func (r *LeaveRepository) Get(id uint) (workdays.Leave, bool, error) {
+	var rows []database.Leave
+	if err := r.db.Where("id = ?", id).Limit(1).Find(&rows).Error; err != nil {
+		return workdays.Leave{}, false, fmt.Errorf("failed to get leave %d: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return workdays.Leave{}, false, nil
+	}
+
+	entry, err := leaveFromRow(rows[0])
+	return entry, err == nil, err
+}
+
+// Create stores entries, all or none. It fails with ErrLeaveExceedsDay when
+// a day would hold more than a whole day of leave.
+func (r *LeaveRepository) Create(entries []workdays.Leave) ([]workdays.Leave, error) {
+	rows := make([]database.Leave, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, database.Leave{
+			Date:     entry.Date.Format("2006-01-02"),
+			Type:     entry.Type,
+			Fraction: entry.Fraction,
+			Note:     entry.Note,
+		})
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range rows {
+			if err := checkDay(tx, rows[i].Date, 0, rows[i].Fraction); err != nil {
+				return err
+			}
+			if err := tx.Create(&rows[i]).Error; err != nil {
+				return fmt.Errorf("failed to create leave: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]workdays.Leave, 0, len(rows))
+	for _, row := range rows {
+		entry, err := leaveFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, entry)
+	}
+	return created, nil
+}
+
+// Update changes the type, fraction and note of the entry with id; ok is
+// false when there is none.
+func (r *LeaveRepository) Update(id uint, leaveType string, fraction float64, note string) (workdays.Leave, bool, error) {
+	var row database.Leave
+	found := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var rows []database.Leave
+		if err := tx.Where("id = ?", id).Limit(1).Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to get leave %d: %w", id, err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		found = true
+
+		row = rows[0]
+		if err := checkDay(tx, row.Date, row.ID, fraction); err != nil {
+			return err
+		}
+
+		row.Type, row.Fraction, row.Note = leaveType, fraction, note
+		if err := tx.Save(&row).Error; err != nil {
+			return fmt.Errorf("failed to update leave %d: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil || !found {
+		return workdays.Leave{}, false, err
+	}
+
+	entry, err := leaveFromRow(row)
+	return entry, true, err
+}
+
+// Delete removes the entry with id; ok is false when there is none.
+func (r *LeaveRepository) Delete(id uint) (bool, error) {
+	result := r.db.Where("id = ?", id).Delete(&database.Leave{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete leave %d: %w", id, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, touchDeleted(r.db)
+}
+
+// checkDay makes sure adding fraction to the leave on date, leaving out the
+// entry being replaced, stays within a day.
+func checkDay(tx *gorm.DB, date string, replacing uint, fraction float64) error {
+	var taken float64
+	err := tx.Model(&database.Leave{}).
+		Where("date = ? AND id <> ?", date, replacing).
+		Select("COALESCE(SUM(fraction), 0)").Scan(&taken).Error
+	if err != nil {
+		return fmt.Errorf("failed to get leave on %s: %w", date, err)
+	}
+
+	// Leave some room for fractions like thirds that don't add up exactly
+	if taken+fraction > 1.0001 {
+		return fmt.Errorf("%w on %s", ErrLeaveExceedsDay, date)
+	}
+	return nil
+}
+
+func leaveFromRow(row database.Leave) (workdays.Leave, error) {
+	date, err := time.Parse("2006-01-02", row.Date)
+	if err != nil {
+		return workdays.Leave{}, fmt.Errorf("invalid leave date %q: %w", row.Date, err)
+	}
+
+	return workdays.Leave{
+		ID:       row.ID,
+		Date:     date,
+		Type:     row.Type,
+		Fraction: row.Fraction,
+		Note:     row.Note,
+	}, nil
+}
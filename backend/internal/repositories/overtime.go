@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"fmt"
+	"math"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/types"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OvertimeRepository keeps the manual overtime adjustments and works out the
+// ledger from the trackers' month snapshots and the working-day calendar.
+type OvertimeRepository struct {
+	db           *gorm.DB
+	trackersRepo *TrackersRepository
+	holidays     *HolidaysRepository
+	preferences  *PreferencesRepository
+
+	// Hours worked in months that are over, by month and timezone, so the
+	// ledger only fetches the current month; see closedHours
+	mu     sync.Mutex
+	closed map[string]closedMonth
+}
+
+type closedMonth struct {
+	hours      float64
+	generation uint64
+}
+
+func NewOvertimeRepository(db *gorm.DB, trackersRepo *TrackersRepository, holidays *HolidaysRepository, preferences *PreferencesRepository) *OvertimeRepository {
+	return &OvertimeRepository{
+		db:           db,
+		trackersRepo: trackersRepo,
+		holidays:     holidays,
+		preferences:  preferences,
+		closed:       make(map[string]closedMonth),
+	}
+}
+
+// Ledger works out every month from the ledger's start up to the end of
+// the day until, in until's timezone. The month of until only expects the
+// working days up to it.
+func (r *OvertimeRepository) Ledger(until time.Time) (types.OvertimeLedger, error) {
+	prefs, err := r.preferences.Get()
+	if err != nil {
+		return types.OvertimeLedger{}, err
+	}
+
+	loc := until.Location()
+	end := time.Date(until.Year(), until.Month(), until.Day()+1, 0, 0, 0, 0, loc)
+	start := time.Date(until.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	if prefs.OvertimeStart != "" {
+		month, err := time.ParseInLocation("2006-01", prefs.OvertimeStart, loc)
+		if err != nil {
+			return types.OvertimeLedger{}, fmt.Errorf("invalid overtime start: %w", err)
+		}
+		start = month
+	}
+
+	ledger := types.OvertimeLedger{
+		Start:  start.Format("2006-01"),
+		Until:  until.Format("2006-01-02"),
+		Months: []types.OvertimeMonth{},
+	}
+	if !start.Before(end) {
+		return ledger, nil
+	}
+
+	adjustments, err := r.adjustmentsByMonth(start.Format("2006-01"), until.Format("2006-01"))
+	if err != nil {
+		return types.OvertimeLedger{}, err
+	}
+
+	// The expected hours always follow the current settings, holidays,
+	// leave and schedules; one calendar covers every month
+	lastMonth := time.Date(until.Year(), until.Month(), 1, 0, 0, 0, 0, loc)
+	calendar, err := r.holidays.Calendar(start, lastMonth.AddDate(0, 1, 0))
+	if err != nil {
+		return types.OvertimeLedger{}, err
+	}
+
+	balance := 0.0
+	for som := start; som.Before(end); som = som.AddDate(0, 1, 0) {
+		nextMonth := som.AddDate(0, 1, 0)
+		monthEnd := nextMonth
+		if end.Before(nextMonth) {
+			monthEnd = end
+		}
+
+		actual, stale, err := r.hours(som, monthEnd)
+		if err != nil {
+			return types.OvertimeLedger{}, err
+		}
+		expected := calendar.ExpectedHours(som, monthEnd)
+		month := som.Format("2006-01")
+		balance += actual - expected + adjustments[month]
+
+		ledger.Months = append(ledger.Months, types.OvertimeMonth{
+			Month:       month,
+			Actual:      round2(actual),
+			Expected:    round2(expected),
+			Delta:       round2(actual - expected),
+			Adjustments: round2(adjustments[month]),
+			Balance:     round2(balance),
+			Partial:     monthEnd.Before(nextMonth),
+			Stale:       stale,
+		})
+	}
+	ledger.Balance = round2(balance)
+
+	return ledger, nil
+}
+
+// hours returns the hours worked from som, the start of a month, to
+// monthEnd, and whether they come from stale snapshots. The hours of a
+// whole month that is over are kept until the trackers' data changes, as
+// long as they weren't stale.
+func (r *OvertimeRepository) hours(som, monthEnd time.Time) (float64, bool, error) {
+	closed := monthEnd.Equal(som.AddDate(0, 1, 0)) && !monthEnd.After(time.Now())
+	key := som.Format("2006-01") + ":" + som.Location().String()
+	generation := r.trackersRepo.Generation()
+
+	if closed {
+		r.mu.Lock()
+		cached, ok := r.closed[key]
+		r.mu.Unlock()
+		if ok && cached.generation == generation {
+			return cached.hours, false, nil
+		}
+	}
+
+	snapshots, err := r.trackersRepo.MonthSnapshots(som)
+	if err != nil {
+		return 0, false, err
+	}
+	hours := snapshots.Hours(som, monthEnd)
+	stale := len(snapshots.Stale(time.Now())) > 0
+
+	if closed && !stale {
+		r.mu.Lock()
+		r.closed[key] = closedMonth{hours: hours, generation: generation}
+		r.mu.Unlock()
+	}
+	return hours, stale, nil
+}
+
+// Adjustments returns the adjustments of the months from first to last,
+// both "2006-01".
+func (r *OvertimeRepository) Adjustments(first, last string) ([]database.OvertimeAdjustment, error) {
+	var adjustments []database.OvertimeAdjustment
+	err := r.db.Where("month >= ? AND month <= ?", first, last).Order("month, id").Find(&adjustments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overtime adjustments: %w", err)
+	}
+	return adjustments, nil
+}
+
+func (r *OvertimeRepository) AddAdjustment(adjustment *database.OvertimeAdjustment) error {
+	if err := r.db.Create(adjustment).Error; err != nil {
+		return fmt.Errorf("failed to add overtime adjustment: %w", err)
+	}
+	return nil
+}
+
+// DeleteAdjustment removes the adjustment with id; ok is false when there is
+// none.
+func (r *OvertimeRepository) DeleteAdjustment(id uint) (bool, error) {
+	result := r.db.Where("id = ?", id).Delete(&database.OvertimeAdjustment{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete overtime adjustment %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, touchDeleted(r.db)
+}
+
+func (r *OvertimeRepository) adjustmentsByMonth(first, last string) (map[string]float64, error) {
+	adjustments, err := r.Adjustments(first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	byMonth := make(map[string]float64)
+	for _, adjustment := range adjustments {
+		byMonth[adjustment.Month] += adjustment.Hours
+	}
+	return byMonth, nil
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
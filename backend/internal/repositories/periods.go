@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/periods"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrPeriodExists is returned when another billing period has the same
+// name.
+var ErrPeriodExists = errors.New("a billing period with that name already exists")
+
+// ErrPeriodNotFound is returned when updating a definition that doesn't
+// exist.
+var ErrPeriodNotFound = errors.New("billing period not found")
+
+// PeriodsRepository stores the billing period definitions.
+type PeriodsRepository struct {
+	db *gorm.DB
+}
+
+func NewPeriodsRepository(db *gorm.DB) *PeriodsRepository {
+	return &PeriodsRepository{
+		db: db,
+	}
+}
+
+// All returns every definition by name.
+func (r *PeriodsRepository) All() ([]periods.Definition, error) {
+	var rows []database.BillingPeriod
+	if err := r.db.Order("name").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get billing periods: %w", err)
+	}
+
+	definitions := make([]periods.Definition, 0, len(rows))
+	for _, row := range rows {
+		definition, err := periodFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// Get returns the definition with id; ok is false when there is none.
+func (r *PeriodsRepository) Get(id uint) (periods.Definition, bool, error) {
+	var rows []database.BillingPeriod
+	if err := r.db.Where("id = ?", id).Limit(1).Find(&rows).Error; err != nil {
+		return periods.Definition{}, false, fmt.Errorf("failed to get billing period %d: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return periods.Definition{}, false, nil
+	}
+
+	definition, err := periodFromRow(rows[0])
+	return definition, err == nil, err
+}
+
+// Save creates definition, or updates it when it has an ID, and sets its
+// ID. Updating a definition that doesn't exist returns ErrPeriodNotFound.
+func (r *PeriodsRepository) Save(definition *periods.Definition) error {
+	row := periodToRow(*definition)
+
+	var taken int64
+	err := r.db.Model(&database.BillingPeriod{}).
+		Where("name = ? AND id <> ?", row.Name, row.ID).Count(&taken).Error
+	if err != nil {
+		return fmt.Errorf("failed to check billing periods: %w", err)
+	}
+	if taken > 0 {
+		return ErrPeriodExists
+	}
+
+	if row.ID == 0 {
+		if err := r.db.Create(&row).Error; err != nil {
+			return fmt.Errorf("failed to create billing period: %w", err)
+		}
+		definition.ID = row.ID
+		return nil
+	}
+
+	var existing []database.BillingPeriod
+	if err := r.db.Where("id = ?", row.ID).Limit(1).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to get billing period %d: %w", row.ID, err)
+	}
+	if len(existing) == 0 {
+		return ErrPeriodNotFound
+	}
+
+	err = r.db.Model(&existing[0]).
+		Select("name", "kind", "start_day", "weeks", "anchor").
+		Updates(&row).Error
+	if err != nil {
+		return fmt.Errorf("failed to update billing period %d: %w", row.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the definition with id; ok is false when there is none.
+func (r *PeriodsRepository) Delete(id uint) (bool, error) {
+	result := r.db.Where("id = ?", id).Delete(&database.BillingPeriod{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete billing period %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, touchDeleted(r.db)
+}
+
+func periodToRow(definition periods.Definition) database.BillingPeriod {
+	row := database.BillingPeriod{
+		ID:       definition.ID,
+		Name:     definition.Name,
+		Kind:     definition.Kind,
+		StartDay: definition.StartDay,
+		Weeks:    definition.Weeks,
+	}
+	if !definition.Anchor.IsZero() {
+		row.Anchor = definition.Anchor.Format("2006-01-02")
+	}
+	return row
+}
+
+func periodFromRow(row database.BillingPeriod) (periods.Definition, error) {
+	definition := periods.Definition{
+		ID:       row.ID,
+		Name:     row.Name,
+		Kind:     row.Kind,
+		StartDay: row.StartDay,
+		Weeks:    row.Weeks,
+	}
+	if row.Anchor != "" {
+		anchor, err := time.Parse("2006-01-02", row.Anchor)
+		if err != nil {
+			return periods.Definition{}, fmt.Errorf("invalid billing period anchor %q: %w", row.Anchor, err)
+		}
+		definition.Anchor = anchor
+	}
+	return definition, nil
+}
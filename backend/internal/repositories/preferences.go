@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"fmt"
+	"myspace/backend/internal/types"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const preferencesPrefix = "preferences."
+
+// PreferencesRepository reads and writes the typed preferences, stored as
+// one Setting row per field under "preferences.<field>". Fields that were
+// never set use the defaults.
+type PreferencesRepository struct {
+	settings *SettingsRepository
+	defaults types.Preferences
+
+	mu     sync.Mutex
+	cached *types.Preferences
+}
+
+func NewPreferencesRepository(settings *SettingsRepository, defaults types.Preferences) *PreferencesRepository {
+	return &PreferencesRepository{
+		settings: settings,
+		defaults: defaults,
+	}
+}
+
+// DefaultPreferences are used for anything the user hasn't set.
+func DefaultPreferences(loc *time.Location) types.Preferences {
+	return types.Preferences{
+		DailyGoal:   8,
+		MonthlyGoal: 160,
+		WeekStart:   "monday",
+		Timezone:    loc.String(),
+		Currency:    "EUR",
+		WorkWeek:    []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+	}
+}
+
+func (r *PreferencesRepository) Get() (types.Preferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached != nil {
+		return clonePreferences(*r.cached), nil
+	}
+
+	prefs := clonePreferences(r.defaults)
+	for key, field := range r.fields(&prefs) {
+		value, ok, err := r.settings.Get(preferencesPrefix + key)
+		if err != nil {
+			return types.Preferences{}, err
+		}
+		if !ok {
+			continue
+		}
+		if err := field.parse(value); err != nil {
+			return types.Preferences{}, fmt.Errorf("invalid stored preference %s: %w", key, err)
+		}
+	}
+
+	r.cached = &prefs
+	return clonePreferences(prefs), nil
+}
+
+// clonePreferences copies the slices of prefs, so callers decoding into the
+// result can't change the cached or default values.
+func clonePreferences(prefs types.Preferences) types.Preferences {
+	prefs.WorkWeek = append([]string(nil), prefs.WorkWeek...)
+	return prefs
+}
+
+// Update validates and saves all fields of prefs.
+func (r *PreferencesRepository) Update(prefs types.Preferences) error {
+	if err := prefs.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cached = nil
+	for key, field := range r.fields(&prefs) {
+		if err := r.settings.Set(preferencesPrefix+key, field.format()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Location returns the preferred timezone, falling back to the default one
+// if the preferences can't be read.
+func (r *PreferencesRepository) Location() *time.Location {
+	prefs, err := r.Get()
+	if err != nil {
+		return r.defaults.Location()
+	}
+	return prefs.Location()
+}
+
+type preferenceField struct {
+	parse  func(value string) error
+	format func() string
+}
+
+func (r *PreferencesRepository) fields(prefs *types.Preferences) map[string]preferenceField {
+	return map[string]preferenceField{
+		"daily_goal":      floatField(&prefs.DailyGoal),
+		"monthly_goal":    floatField(&prefs.MonthlyGoal),
+		"week_start":      stringField(&prefs.WeekStart),
+		"timezone":        stringField(&prefs.Timezone),
+		"currency":        stringField(&prefs.Currency),
+		"work_week":       listField(&prefs.WorkWeek),
+		"holiday_country": stringField(&prefs.HolidayCountry),
+		"vacation_days":   floatField(&prefs.VacationDays),
+		"overtime_start":  stringField(&prefs.OvertimeStart),
+		"earnings_target": floatField(&prefs.EarningsTarget),
+	}
+}
+
+func floatField(target *float64) preferenceField {
+	return preferenceField{
+		parse: func(value string) (err error) {
+			*target, err = strconv.ParseFloat(value, 64)
+			return err
+		},
+		format: func() string {
+			return strconv.FormatFloat(*target, 'f', -1, 64)
+		},
+	}
+}
+
+func stringField(target *string) preferenceField {
+	return preferenceField{
+		parse: func(value string) error {
+			*target = value
+			return nil
+		},
+		format: func() string {
+			return *target
+		},
+	}
+}
+
+// listField stores a list as comma-separated values.
+func listField(target *[]string) preferenceField {
+	return preferenceField{
+		parse: func(value string) error {
+			*target = nil
+			if value != "" {
+				*target = strings.Split(value, ",")
+			}
+			return nil
+		},
+		format: func() string {
+			return strings.Join(*target, ",")
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/rates"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrRateExists is returned when another rate for the same project or
+// source takes effect on the same day.
+var ErrRateExists = errors.New("a rate for that project already takes effect on that day")
+
+// ErrRateNotFound is returned when updating a rate that doesn't exist.
+var ErrRateNotFound = errors.New("hourly rate not found")
+
+// RatesRepository stores the effective-dated hourly rates.
+type RatesRepository struct {
+	db *gorm.DB
+}
+
+func NewRatesRepository(db *gorm.DB) *RatesRepository {
+	return &RatesRepository{
+		db: db,
+	}
+}
+
+// All returns every rate by source, project and date.
+func (r *RatesRepository) All() ([]rates.Rate, error) {
+	var rows []database.HourlyRate
+	if err := r.db.Order("source, project_id, effective_from").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get hourly rates: %w", err)
+	}
+
+	list := make([]rates.Rate, 0, len(rows))
+	for _, row := range rows {
+		rate, err := rateFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rate)
+	}
+	return list, nil
+}
+
+// Table returns all rates ready for pricing entries.
+func (r *RatesRepository) Table() (*rates.Table, error) {
+	list, err := r.All()
+	if err != nil {
+		return nil, err
+	}
+	return rates.New(list), nil
+}
+
+// Get returns the rate with id; ok is false when there is none.
+func (r *RatesRepository) Get(id uint) (rates.Rate, bool, error) {
+	var rows []database.HourlyRate
+	if err := r.db.Where("id = ?", id).Limit(1).Find(&rows).Error; err != nil {
+		return rates.Rate{}, false, fmt.Errorf("failed to get hourly rate %d: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return rates.Rate{}, false, nil
+	}
+
+	rate, err := rateFromRow(rows[0])
+	return rate, err == nil, err
+}
+
+// Save creates rate, or updates it when it has an ID, and sets its ID.
+// Updating a rate that doesn't exist returns ErrRateNotFound.
+func (r *RatesRepository) Save(rate *rates.Rate) error {
+	row := rateToRow(*rate)
+
+	var taken int64
+	err := r.db.Model(&database.HourlyRate{}).
+		Where("source = ? AND project_id = ? AND effective_from = ? AND id <> ?", row.Source, row.ProjectID, row.EffectiveFrom, row.ID).
+		Count(&taken).Error
+	if err != nil {
+		return fmt.Errorf("failed to check hourly rates: %w", err)
+	}
+	if taken > 0 {
+		return ErrRateExists
+	}
+
+	if row.ID == 0 {
+		if err := r.db.Create(&row).Error; err != nil {
+			return fmt.Errorf("failed to create hourly rate: %w", err)
+		}
+		rate.ID = row.ID
+		return nil
+	}
+
+	var existing []database.HourlyRate
+	if err := r.db.Where("id = ?", row.ID).Limit(1).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to get hourly rate %d: %w", row.ID, err)
+	}
+	if len(existing) == 0 {
+		return ErrRateNotFound
+	}
+
+	err = r.db.Model(&existing[0]).
+		Select("source", "project_id", "effective_from", "rate", "currency").
+		Updates(&row).Error
+	if err != nil {
+		return fmt.Errorf("failed to update hourly rate %d: %w", row.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the rate with id; ok is false when there is none.
+func (r *RatesRepository) Delete(id uint) (bool, error) {
+	result := r.db.Where("id = ?", id).Delete(&database.HourlyRate{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete hourly rate %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, touchDeleted(r.db)
+}
+
+func rateToRow(rate rates.Rate) database.HourlyRate {
+	return database.HourlyRate{
+		ID:            rate.ID,
+		Source:        rate.Source,
+		ProjectID:     rate.ProjectID,
+		EffectiveFrom: rate.EffectiveFrom.Format("2006-01-02"),
+		Rate:          rate.Hourly,
+		Currency:      rate.Currency,
+	}
+}
+
+func rateFromRow(row database.HourlyRate) (rates.Rate, error) {
+	effectiveFrom, err := time.Parse("2006-01-02", row.EffectiveFrom)
+	if err != nil {
+		return rates.Rate{}, fmt.Errorf("invalid hourly rate date %q: %w", row.EffectiveFrom, err)
+	}
+
+	return rates.Rate{
+		ID:            row.ID,
+		Source:        row.Source,
+		ProjectID:     row.ProjectID,
+		Hourly:        row.Rate,
+		Currency:      row.Currency,
+		EffectiveFrom: effectiveFrom,
+	}, nil
+}
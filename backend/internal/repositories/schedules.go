@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/workdays"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrProfileExists is returned when another profile takes effect on the
+// same day.
+var ErrProfileExists = errors.New("a schedule profile already takes effect on that day")
+
+// ErrProfileNotFound is returned when updating a profile that doesn't exist.
+var ErrProfileNotFound = errors.New("schedule profile not found")
+
+// SchedulesRepository stores the effective-dated schedule profiles.
+type SchedulesRepository struct {
+	db *gorm.DB
+}
+
+func NewSchedulesRepository(db *gorm.DB) *SchedulesRepository {
+	return &SchedulesRepository{
+		db: db,
+	}
+}
+
+// All returns every profile, oldest first. There are only ever a handful,
+// so calendars load them all and pick the one in effect per day.
+func (r *SchedulesRepository) All() ([]workdays.Profile, error) {
+	var rows []database.ScheduleProfile
+	if err := r.db.Order("effective_from").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get schedule profiles: %w", err)
+	}
+
+	profiles := make([]workdays.Profile, 0, len(rows))
+	for _, row := range rows {
+		profile, err := profileFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// Get returns the profile with id; ok is false when there is none.
+func (r *SchedulesRepository) Get(id uint) (workdays.Profile, bool, error) {
+	var rows []database.ScheduleProfile
+	if err := r.db.Where("id = ?", id).Limit(1).Find(&rows).Error; err != nil {
+		return workdays.Profile{}, false, fmt.Errorf("failed to get schedule profile %d: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return workdays.Profile{}, false, nil
+	}
+
+	profile, err := profileFromRow(rows[0])
+	return profile, err == nil, err
+}
+
+// Save creates profile, or updates it when it has an ID, and sets its ID.
+// Updating a profile that doesn't exist returns ErrProfileNotFound.
+func (r *SchedulesRepository) Save(profile *workdays.Profile) error {
+	row := profileToRow(*profile)
+
+	var taken int64
+	err := r.db.Model(&database.ScheduleProfile{}).
+		Where("effective_from = ? AND id <> ?", row.EffectiveFrom, row.ID).Count(&taken).Error
+	if err != nil {
+		return fmt.Errorf("failed to check schedule profiles: %w", err)
+	}
+	if taken > 0 {
+		return ErrProfileExists
+	}
+
+	if row.ID == 0 {
+		if err := r.db.Create(&row).Error; err != nil {
+			return fmt.Errorf("failed to create schedule profile: %w", err)
+		}
+		profile.ID = row.ID
+		return nil
+	}
+
+	var existing []database.ScheduleProfile
+	if err := r.db.Where("id = ?", row.ID).Limit(1).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to get schedule profile %d: %w", row.ID, err)
+	}
+	if len(existing) == 0 {
+		return ErrProfileNotFound
+	}
+
+	err = r.db.Model(&existing[0]).
+		Select("name", "effective_from", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday").
+		Updates(&row).Error
+	if err != nil {
+		return fmt.Errorf("failed to update schedule profile %d: %w", row.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the profile with id; ok is false when there is none.
+func (r *SchedulesRepository) Delete(id uint) (bool, error) {
+	result := r.db.Where("id = ?", id).Delete(&database.ScheduleProfile{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete schedule profile %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, touchDeleted(r.db)
+}
+
+func profileToRow(profile workdays.Profile) database.ScheduleProfile {
+	return database.ScheduleProfile{
+		ID:            profile.ID,
+		Name:          profile.Name,
+		EffectiveFrom: profile.EffectiveFrom.Format("2006-01-02"),
+		Sunday:        profile.Hours[time.Sunday],
+		Monday:        profile.Hours[time.Monday],
+		Tuesday:       profile.Hours[time.Tuesday],
+		Wednesday:     profile.Hours[time.Wednesday],
+		Thursday:      profile.Hours[time.Thursday],
+		Friday:        profile.Hours[time.Friday],
+		Saturday:      profile.Hours[time.Saturday],
+	}
+}
+
+func profileFromRow(row database.ScheduleProfile) (workdays.Profile, error) {
+	effectiveFrom, err := time.Parse("2006-01-02", row.EffectiveFrom)
+	if err != nil {
+		return workdays.Profile{}, fmt.Errorf("invalid schedule profile date %q: %w", row.EffectiveFrom, err)
+	}
+
+	return workdays.Profile{
+		ID:            row.ID,
+		Name:          row.Name,
+		EffectiveFrom: effectiveFrom,
+		Hours: [7]float64{
+			time.Sunday:    row.Sunday,
+			time.Monday:    row.Monday,
+			time.Tuesday:   row.Tuesday,
+			time.Wednesday: row.Wednesday,
+			time.Thursday:  row.Thursday,
+			time.Friday:    row.Friday,
+			time.Saturday:  row.Saturday,
+		},
+	}, nil
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"fmt"
+	"myspace/backend/internal/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettingsRepository reads and writes raw key/value rows of the Setting
+// table.
+type SettingsRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingsRepository(db *gorm.DB) *SettingsRepository {
+	return &SettingsRepository{
+		db: db,
+	}
+}
+
+// Get returns the value for key; ok is false when the key isn't set.
+func (r *SettingsRepository) Get(key string) (string, bool, error) {
+	var settings []database.Setting
+	if err := r.db.Where("key = ?", key).Limit(1).Find(&settings).Error; err != nil {
+		return "", false, fmt.Errorf("failed to get setting %s: %w", key, err)
+	}
+	if len(settings) == 0 {
+		return "", false, nil
+	}
+
+	return settings[0].Value, true, nil
+}
+
+func (r *SettingsRepository) Set(key, value string) error {
+	setting := database.Setting{Key: key, Value: value}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&setting).Error
+	if err != nil {
+		return fmt.Errorf("failed to set setting %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (r *SettingsRepository) Delete(key string) error {
+	if err := r.db.Where("key = ?", key).Delete(&database.Setting{}).Error; err != nil {
+		return fmt.Errorf("failed to delete setting %s: %w", key, err)
+	}
+
+	return nil
+}
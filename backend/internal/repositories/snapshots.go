@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/types"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SnapshotsRepository keeps the last successful month snapshot per tracker,
+// month and timezone.
+type SnapshotsRepository struct {
+	db *gorm.DB
+}
+
+func NewSnapshotsRepository(db *gorm.DB) *SnapshotsRepository {
+	return &SnapshotsRepository{
+		db: db,
+	}
+}
+
+// SaveSnapshot stores snapshot as the latest one of the month starting at
+// som. The month is keyed by som rather than the snapshot's own range, whose
+// timezone doesn't survive caching.
+func (r *SnapshotsRepository) SaveSnapshot(som time.Time, snapshot *types.MonthSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	row := database.Snapshot{
+		Source:    snapshot.Source,
+		Month:     som.Format("2006-01"),
+		Location:  som.Location().String(),
+		Data:      data,
+		FetchedAt: snapshot.FetchedAt.UTC(),
+	}
+	err = r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source"}, {Name: "month"}, {Name: "location"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "fetched_at", "updated_at"}),
+	}).Create(&row).Error
+	if err != nil {
+		return fmt.Errorf("failed to save %s snapshot: %w", snapshot.Source, err)
+	}
+
+	return nil
+}
+
+// LatestSnapshot returns the saved snapshot of the month starting at som;
+// ok is false when there is none.
+func (r *SnapshotsRepository) LatestSnapshot(source string, som time.Time) (*types.MonthSnapshot, bool, error) {
+	var rows []database.Snapshot
+	err := r.db.Where("source = ? AND month = ? AND location = ?", source, som.Format("2006-01"), som.Location().String()).
+		Limit(1).Find(&rows).Error
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get %s snapshot: %w", source, err)
+	}
+	if len(rows) == 0 {
+		return nil, false, nil
+	}
+
+	var snapshot types.MonthSnapshot
+	if err := json.Unmarshal(rows[0].Data, &snapshot); err != nil {
+		return nil, false, fmt.Errorf("failed to decode %s snapshot: %w", source, err)
+	}
+
+	return &snapshot, true, nil
+}
@@ -1,23 +1,34 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"myspace/backend/internal/cache"
 	"myspace/backend/internal/config"
 	"myspace/backend/internal/interfaces"
 	"myspace/backend/internal/trackers"
 	"myspace/backend/internal/types"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type TrackersRepository struct {
-	trackers []interfaces.TimeTracker
-	config   *config.Config
+	trackers   []interfaces.TimeTracker
+	remote     []interfaces.TimeTracker
+	cache      cache.Store
+	config     *config.Config
+	logger     *slog.Logger
+	generation atomic.Uint64
 }
 
-func NewTrackersRepository(cfg *config.Config) *TrackersRepository {
+func NewTrackersRepository(cfg *config.Config, logger *slog.Logger) *TrackersRepository {
 	repo := &TrackersRepository{
 		trackers: make([]interfaces.TimeTracker, 0),
 		config:   cfg,
+		logger:   logger,
 	}
 	repo.hydrate()
 	return repo
@@ -25,25 +36,112 @@ func NewTrackersRepository(cfg *config.Config) *TrackersRepository {
 
 func (tr *TrackersRepository) hydrate() {
 	if tr.config.Mayven.Auth != "" {
-		fmt.Printf("Adding Mayven tracker with auth: %s...\n", tr.config.Mayven.Auth[:50])
-		tr.addTracker(trackers.NewMayven(tr.config))
+		tr.logger.Info("adding tracker", "tracker", "mayven")
+		tr.addTracker(trackers.NewMayven(tr.config, tr.logger))
 	} else {
-		fmt.Println("No Mayven auth found, skipping Mayven tracker")
+		tr.logger.Info("no credentials, skipping tracker", "tracker", "mayven")
 	}
 	
 	if tr.config.Everhour.Token != "" {
-		tr.addTracker(trackers.NewEverhour(tr.config))
+		tr.logger.Info("adding tracker", "tracker", "everhour")
+		tr.addTracker(trackers.NewEverhour(tr.config, tr.logger))
 	}
 	
 	if tr.config.Clockify.Token != "" {
-		tr.addTracker(trackers.NewClockify(tr.config))
+		tr.logger.Info("adding tracker", "tracker", "clockify")
+		tr.addTracker(trackers.NewClockify(tr.config, tr.logger))
 	}
 	
-	fmt.Printf("Total trackers initialized: %d\n", len(tr.trackers))
+	tr.logger.Info("trackers initialized", "count", len(tr.trackers))
 }
 
 func (tr *TrackersRepository) addTracker(tracker interfaces.TimeTracker) {
 	tr.trackers = append(tr.trackers, tracker)
+	tr.remote = append(tr.remote, tracker)
+}
+
+// Remote returns the provider-backed trackers, bypassing any local store.
+func (tr *TrackersRepository) Remote() []interfaces.TimeTracker {
+	return tr.remote
+}
+
+// wrap decorates every tracker used for reads.
+func (tr *TrackersRepository) wrap(decorate func(interfaces.TimeTracker) interfaces.TimeTracker) {
+	for i, tracker := range tr.trackers {
+		tr.trackers[i] = decorate(tracker)
+	}
+}
+
+// UseCache caches tracker answers in store.
+func (tr *TrackersRepository) UseCache(store cache.Store, ttls trackers.CacheTTLs) {
+	tr.cache = store
+	tr.wrap(func(tracker interfaces.TimeTracker) interfaces.TimeTracker {
+		return trackers.NewCached(tracker, store, ttls)
+	})
+}
+
+// Generation changes whenever the tracked data may have changed, so
+// results worked out from it can tell they need to be worked out again.
+func (tr *TrackersRepository) Generation() uint64 {
+	return tr.generation.Load()
+}
+
+// Changed marks the tracked data as changed, e.g. after a sync.
+func (tr *TrackersRepository) Changed() {
+	tr.generation.Add(1)
+}
+
+// InvalidateCache drops cached answers for a source, or for all sources
+// when source is empty.
+func (tr *TrackersRepository) InvalidateCache(source string) {
+	tr.Changed()
+	if tr.cache == nil {
+		return
+	}
+	
+	prefix := trackers.CachePrefix(source)
+	if source == "" {
+		prefix = "tracker:"
+	}
+	tr.cache.DeletePrefix(prefix)
+}
+
+// Sources returns the configured tracker sources.
+func (tr *TrackersRepository) Sources() []string {
+	sources := make([]string, len(tr.remote))
+	for i, tracker := range tr.remote {
+		sources[i] = tracker.GetSource()
+	}
+	return sources
+}
+
+// WebhookReceiver returns the configured tracker for source if it accepts
+// webhooks.
+func (tr *TrackersRepository) WebhookReceiver(source string) (interfaces.WebhookReceiver, bool) {
+	for _, tracker := range tr.remote {
+		if tracker.GetSource() != source {
+			continue
+		}
+		receiver, ok := tracker.(interfaces.WebhookReceiver)
+		return receiver, ok
+	}
+	return nil, false
+}
+
+// ServeFrom makes reads come from the local store of synced entries; only
+// running timers still go to the providers.
+func (tr *TrackersRepository) ServeFrom(store trackers.TrackStore) {
+	tr.wrap(func(tracker interfaces.TimeTracker) interfaces.TimeTracker {
+		return trackers.NewStored(tracker, store)
+	})
+}
+
+// UseFallback keeps the last good month snapshot of every tracker in store
+// and serves it, marked stale, while the tracker is failing.
+func (tr *TrackersRepository) UseFallback(store trackers.SnapshotStore) {
+	tr.wrap(func(tracker interfaces.TimeTracker) interfaces.TimeTracker {
+		return trackers.NewFallback(tracker, store, tr.logger)
+	})
 }
 
 func (tr *TrackersRepository) Hours(from, to time.Time) (float64, error) {
@@ -74,30 +172,154 @@ func (tr *TrackersRepository) RunningHours() (float64, error) {
 	return float64(totalSeconds) / 3600, nil
 }
 
-func (tr *TrackersRepository) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
-	var projectTimes types.ProjectTimeList
+// RunningTimers returns the timers running right now, one per tracker at
+// most.
+func (tr *TrackersRepository) RunningTimers() []*types.RunningTimer {
+	var timers []*types.RunningTimer
 	
 	for _, tracker := range tr.trackers {
-		times, err := tracker.GetMonthlyTimeByProject(dayOfMonth)
+		timer, err := tracker.GetRunningTimer()
 		if err != nil {
+			tr.logger.Warn("failed to get running timer", "tracker", tracker.GetSource(), "error", err)
 			continue
 		}
-		projectTimes.Merge(times)
+		if timer != nil {
+			timers = append(timers, timer)
+		}
+	}
+	
+	return timers
+}
+
+func (tr *TrackersRepository) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	snapshots, err := tr.MonthSnapshots(dayOfMonth)
+	if err != nil {
+		return nil, err
 	}
 	
-	return projectTimes, nil
+	return snapshots.Projects(), nil
 }
 
 func (tr *TrackersRepository) GetDailyHours(dayOfMonth time.Time) (map[string]*float64, error) {
-	var projectTimes types.ProjectTimeList
+	snapshots, err := tr.MonthSnapshots(dayOfMonth)
+	if err != nil {
+		return nil, err
+	}
 	
-	for _, tracker := range tr.trackers {
-		times, err := tracker.GetMonthIntervals(dayOfMonth)
+	return snapshots.Intervals().GetDailyHours(dayOfMonth), nil
+}
+
+// MonthSnapshots fetches one snapshot of the month containing dayOfMonth
+// per tracker, running the trackers concurrently. Trackers that fail are
+// left out.
+func (tr *TrackersRepository) MonthSnapshots(dayOfMonth time.Time) (types.MonthSnapshots, error) {
+	results := make(types.MonthSnapshots, len(tr.trackers))
+	var wg sync.WaitGroup
+	
+	for i, tracker := range tr.trackers {
+		wg.Add(1)
+		go func(i int, tracker interfaces.TimeTracker) {
+			defer wg.Done()
+			
+			snapshot, err := tracker.GetMonthSnapshot(dayOfMonth)
+			if err != nil {
+				tr.logger.Warn("failed to get month snapshot", "tracker", tracker.GetSource(), "error", err)
+				return
+			}
+			results[i] = snapshot
+		}(i, tracker)
+	}
+	wg.Wait()
+	
+	var snapshots types.MonthSnapshots
+	for _, snapshot := range results {
+		if snapshot != nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	
+	return snapshots, nil
+}
+
+// RangeSnapshots fetches the month snapshots of every month [from, to)
+// touches, for ranges that aren't calendar months.
+func (tr *TrackersRepository) RangeSnapshots(from, to time.Time) (types.MonthSnapshots, error) {
+	var snapshots types.MonthSnapshots
+
+	som := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	for ; som.Before(to); som = som.AddDate(0, 1, 0) {
+		month, err := tr.MonthSnapshots(som)
 		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, month...)
+	}
+
+	return snapshots, nil
+}
+
+// ErrTrackersUnavailable is returned by BillableTimes when a selected
+// tracker failed, since a bill must not leave its hours out.
+var ErrTrackersUnavailable = errors.New("trackers unavailable")
+
+// BillableTimes fetches the entries of [from, to) from the selected sources
+// with their per-project breakdown of the same range, so sources whose
+// entries carry no project can still be priced per project. Unlike the
+// views, it fails when any selected tracker does; the error wraps
+// ErrTrackersUnavailable and names them.
+func (tr *TrackersRepository) BillableTimes(from, to time.Time, sources []string) (types.ProjectTimeList, types.ProjectTimeList, error) {
+	selected := make(map[string]bool)
+	for _, source := range sources {
+		selected[source] = true
+	}
+
+	type result struct {
+		entries  types.ProjectTimeList
+		projects types.ProjectTimeList
+		err      error
+	}
+	results := make([]result, len(tr.trackers))
+	var wg sync.WaitGroup
+
+	for i, tracker := range tr.trackers {
+		if !selected[tracker.GetSource()] {
 			continue
 		}
-		projectTimes.Merge(times)
+
+		wg.Add(1)
+		go func(i int, tracker interfaces.TimeTracker) {
+			defer wg.Done()
+
+			entries, err := tracker.GetIntervals(from, to)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			projects, err := tracker.GetTimeByProject(from, to)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			results[i] = result{entries: entries, projects: projects}
+		}(i, tracker)
 	}
-	
-	return projectTimes.GetDailyHours(dayOfMonth), nil
-}
\ No newline at end of file
+	wg.Wait()
+
+	var entries, projects types.ProjectTimeList
+	var failed []string
+	for i, result := range results {
+		if result.err != nil {
+			source := tr.trackers[i].GetSource()
+			tr.logger.Warn("failed to get billable times", "tracker", source, "error", result.err)
+			failed = append(failed, source)
+			continue
+		}
+		entries.Merge(result.entries)
+		projects.Merge(result.projects)
+	}
+	if len(failed) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrTrackersUnavailable, strings.Join(failed, ", "))
+	}
+
+	return entries, projects, nil
+}
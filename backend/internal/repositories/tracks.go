@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"fmt"
+	"myspace/backend/internal/database"
+	"myspace/backend/internal/types"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TracksRepository stores synced time entries in the local Track and
+// Project tables. Times are stored in UTC so they compare correctly as
+// text in SQLite.
+type TracksRepository struct {
+	db *gorm.DB
+
+	mu       sync.Mutex
+	trackers map[string]uint
+}
+
+func NewTracksRepository(db *gorm.DB) *TracksRepository {
+	return &TracksRepository{
+		db:       db,
+		trackers: make(map[string]uint),
+	}
+}
+
+// TrackerID returns the Tracker row for a source, creating it on first use.
+func (r *TracksRepository) TrackerID(source string) (uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.trackers[source]; ok {
+		return id, nil
+	}
+
+	tracker := database.Tracker{Name: source, Type: source, Config: "{}"}
+	if err := r.db.Where(database.Tracker{Type: source}).FirstOrCreate(&tracker).Error; err != nil {
+		return 0, fmt.Errorf("failed to get tracker %s: %w", source, err)
+	}
+
+	r.trackers[source] = tracker.ID
+	return tracker.ID, nil
+}
+
+// ReplaceWindow upserts the entries of one source for [from, to), keyed by
+// provider entry ID, and removes stored entries in the window the provider
+// no longer returns.
+func (r *TracksRepository) ReplaceWindow(source string, from, to time.Time, entries types.ProjectTimeList) error {
+	trackerID, err := r.TrackerID(source)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		projects := make(map[string]uint)
+		keep := make([]uint, 0, len(entries))
+
+		for _, entry := range entries {
+			if entry.Datetime == nil {
+				continue
+			}
+
+			projectID, ok := projects[entry.ProjectID]
+			if !ok {
+				projectID, err = r.upsertProject(tx, trackerID, entry)
+				if err != nil {
+					return err
+				}
+				projects[entry.ProjectID] = projectID
+			}
+
+			track := newTrack(trackerID, projectID, entry)
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "tracker_id"}, {Name: "external_id"}, {Name: "start"}},
+				DoUpdates: clause.AssignmentColumns([]string{"project_id", "end", "seconds", "description", "updated_at"}),
+			}).Create(&track).Error
+			if err != nil {
+				return fmt.Errorf("failed to upsert track: %w", err)
+			}
+
+			if track.ID == 0 {
+				if err := tx.Where("tracker_id = ? AND external_id = ? AND start = ?", trackerID, track.ExternalID, track.Start).
+					Select("id").First(&track).Error; err != nil {
+					return fmt.Errorf("failed to find track: %w", err)
+				}
+			}
+			keep = append(keep, track.ID)
+		}
+
+		stale := tx.Where("tracker_id = ? AND start >= ? AND start < ?", trackerID, from.UTC(), to.UTC())
+		if len(keep) > 0 {
+			stale = stale.Where("id NOT IN ?", keep)
+		}
+		if err := stale.Delete(&database.Track{}).Error; err != nil {
+			return fmt.Errorf("failed to delete stale tracks: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ReplaceEntry replaces every stored part of one provider entry with parts,
+// which are the entry already split at sync window boundaries. No parts
+// deletes the entry.
+func (r *TracksRepository) ReplaceEntry(source, externalID string, parts types.ProjectTimeList) error {
+	trackerID, err := r.TrackerID(source)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tracker_id = ? AND external_id = ?", trackerID, externalID).Delete(&database.Track{}).Error; err != nil {
+			return fmt.Errorf("failed to delete track %s: %w", externalID, err)
+		}
+
+		for _, part := range parts {
+			if part.Datetime == nil {
+				continue
+			}
+
+			projectID, err := r.upsertProject(tx, trackerID, part)
+			if err != nil {
+				return err
+			}
+
+			track := newTrack(trackerID, projectID, part)
+			if err := tx.Create(&track).Error; err != nil {
+				return fmt.Errorf("failed to create track %s: %w", externalID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func newTrack(trackerID, projectID uint, entry types.ProjectTime) database.Track {
+	track := database.Track{
+		TrackerID:   trackerID,
+		ProjectID:   projectID,
+		ExternalID:  entry.EntryID,
+		Start:       entry.Datetime.UTC(),
+		Seconds:     entry.Seconds,
+		Description: entry.Description,
+	}
+	if entry.End != nil {
+		end := entry.End.UTC()
+		track.End = &end
+	}
+	return track
+}
+
+func (r *TracksRepository) upsertProject(tx *gorm.DB, trackerID uint, entry types.ProjectTime) (uint, error) {
+	project := database.Project{
+		TrackerID:  trackerID,
+		ExternalID: entry.ProjectID,
+		Name:       entry.ProjectTitle,
+	}
+
+	err := tx.Where(database.Project{TrackerID: trackerID, ExternalID: entry.ProjectID}).
+		Assign(database.Project{Name: entry.ProjectTitle}).
+		FirstOrCreate(&project).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert project: %w", err)
+	}
+
+	return project.ID, nil
+}
+
+// GetIntervals returns the stored entries of a source overlapping
+// [from, to), clipped to it.
+func (r *TracksRepository) GetIntervals(source string, from, to time.Time) (types.ProjectTimeList, error) {
+	trackerID, err := r.TrackerID(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stored rows never span more than one sync window, so a month of slack
+	// before from is enough to catch entries that started earlier.
+	var tracks []database.Track
+	err = r.db.Preload("Project").
+		Where("tracker_id = ? AND start < ? AND start >= ?", trackerID, to.UTC(), from.AddDate(0, -1, 0).UTC()).
+		Order("start").
+		Find(&tracks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tracks: %w", err)
+	}
+
+	var projectTimes types.ProjectTimeList
+	for _, track := range tracks {
+		start := track.Start.In(from.Location())
+		projectTime := types.ProjectTime{
+			Source:       source,
+			ProjectID:    track.Project.ExternalID,
+			ProjectTitle: track.Project.Name,
+			Seconds:      track.Seconds,
+			Datetime:     &start,
+			EntryID:      track.ExternalID,
+			Description:  track.Description,
+		}
+		if track.End != nil {
+			end := track.End.In(from.Location())
+			projectTime.End = &end
+		}
+		projectTimes.Add(projectTime)
+	}
+
+	return projectTimes.Clip(from, to), nil
+}
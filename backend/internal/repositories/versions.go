@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"fmt"
+	"myspace/backend/internal/database"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// deletedKey is the setting that records when edited rows were last
+// deleted, since a deleted row leaves no updated_at behind.
+const deletedKey = "data.deleted_at"
+
+// editedModels are the tables edited through the API whose rows change what
+// the views answer alongside the tracked hours. Of the settings only the
+// preferences count, the rest hold job state and sync checkpoints.
+var editedModels = []interface{}{
+	&database.Holiday{},
+	&database.Leave{},
+	&database.OvertimeAdjustment{},
+	&database.ScheduleProfile{},
+	&database.BillingPeriod{},
+	&database.HourlyRate{},
+}
+
+// VersionsRepository tells when the goals, leave, schedules, rates and the
+// rest of the edited data last changed, so views of tracked hours can be
+// cached until they do.
+type VersionsRepository struct {
+	db      *gorm.DB
+	started time.Time
+}
+
+func NewVersionsRepository(db *gorm.DB) *VersionsRepository {
+	return &VersionsRepository{
+		db:      db,
+		started: time.Now(),
+	}
+}
+
+// Modified is the latest updated_at among the edited tables, deletions
+// included. It is never before the server started, since a new version
+// may compute the same data differently.
+func (r *VersionsRepository) Modified() (time.Time, error) {
+	queries := []*gorm.DB{
+		r.db.Model(&database.Setting{}).Where("key LIKE ? OR key = ?", preferencesPrefix+"%", deletedKey),
+	}
+	for _, model := range editedModels {
+		queries = append(queries, r.db.Model(model))
+	}
+
+	modified := r.started
+	for _, query := range queries {
+		var rows []struct {
+			UpdatedAt time.Time
+		}
+		if err := query.Select("updated_at").Order("updated_at DESC").Limit(1).Find(&rows).Error; err != nil {
+			return time.Time{}, fmt.Errorf("failed to get last update: %w", err)
+		}
+		if len(rows) > 0 && rows[0].UpdatedAt.After(modified) {
+			modified = rows[0].UpdatedAt
+		}
+	}
+
+	return modified, nil
+}
+
+// touchDeleted records that edited rows were deleted, for Modified.
+func touchDeleted(db *gorm.DB) error {
+	return NewSettingsRepository(db).Set(deletedKey, time.Now().UTC().Format(time.RFC3339))
+}
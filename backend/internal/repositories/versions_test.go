@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"myspace/backend/internal/workdays"
+	"testing"
+	"time"
+)
+
+func TestVersionsModified(t *testing.T) {
+	db := newDB(t)
+	versions := NewVersionsRepository(db)
+	leave := NewLeaveRepository(db)
+	settings := NewSettingsRepository(db)
+
+	modified := func() time.Time {
+		t.Helper()
+		at, err := versions.Modified()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+
+	started := modified()
+	if !started.Equal(versions.started) {
+		t.Fatalf("Modified() = %v without edits, want the start %v", started, versions.started)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	entries, err := leave.Create([]workdays.Leave{{Date: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Type: workdays.LeaveVacation, Fraction: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := modified()
+	if !created.After(started) {
+		t.Fatalf("Modified() = %v after creating leave, want after %v", created, started)
+	}
+
+	// Job state and sync checkpoints don't change what the views answer
+	time.Sleep(10 * time.Millisecond)
+	if err := settings.Set("scheduler.report", "{}"); err != nil {
+		t.Fatal(err)
+	}
+	if got := modified(); !got.Equal(created) {
+		t.Errorf("Modified() = %v after a job setting, want %v", got, created)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if deleted, err := leave.Delete(entries[0].ID); err != nil || !deleted {
+		t.Fatalf("Delete() = %v, %v", deleted, err)
+	}
+	if got := modified(); !got.After(created) {
+		t.Errorf("Modified() = %v after deleting leave, want after %v", got, created)
+	}
+}
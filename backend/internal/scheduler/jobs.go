@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/syncer"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SyncJob syncs the recent months into the local store and tells
+// trackersRepo its data may have changed.
+func SyncJob(engine *syncer.Engine, trackersRepo *repositories.TrackersRepository, months int) JobFunc {
+	return func(ctx context.Context) error {
+		defer trackersRepo.Changed()
+		return engine.SyncRecent(ctx, months)
+	}
+}
+
+// WarmupJob loads the current month and the running timers so the first
+// page load after a cache expiry doesn't wait on the providers.
+func WarmupJob(trackersRepo *repositories.TrackersRepository, loc *time.Location) JobFunc {
+	return func(ctx context.Context) error {
+		if _, err := trackersRepo.MonthSnapshots(time.Now().In(loc)); err != nil {
+			return err
+		}
+		_, err := trackersRepo.RunningHours()
+		return err
+	}
+}
+
+// ReportJob writes last month's report, grouped by project and day, to
+// dir/YYYY-MM.json. Sources served from their last known data are listed
+// under stale, as in the views.
+func ReportJob(trackersRepo *repositories.TrackersRepository, loc *time.Location, dir string) JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now().In(loc)
+		to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		from := to.AddDate(0, -1, 0)
+
+		snapshots, err := trackersRepo.RangeSnapshots(from, to)
+		if err != nil {
+			return err
+		}
+		projectTimes := snapshots.Intervals().Clip(from, to)
+
+		groupBy := []string{"project", "day"}
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"from":        from.Format("2006-01-02"),
+			"to":          to.AddDate(0, 0, -1).Format("2006-01-02"),
+			"group_by":    groupBy,
+			"total_hours": projectTimes.GetHours(),
+			"groups":      projectTimes.GroupBy(groupBy, loc),
+			"stale":       snapshots.Stale(now),
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create reports directory: %w", err)
+		}
+		path := filepath.Join(dir, from.Format("2006-01")+".json")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,266 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myspace/backend/internal/repositories"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownJob = errors.New("unknown job")
+	ErrRunning    = errors.New("job is already running")
+)
+
+// JobFunc is the work of a job.
+type JobFunc func(ctx context.Context) error
+
+// JobState is what is remembered about a job's runs across restarts.
+type JobState struct {
+	LastRun      *time.Time `json:"last_run"`
+	LastDuration float64    `json:"last_duration_seconds"`
+	LastError    string     `json:"last_error"`
+	LastSuccess  *time.Time `json:"last_success"`
+}
+
+// JobStatus describes a job for listing.
+type JobStatus struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Running  bool       `json:"running"`
+	NextRun  *time.Time `json:"next_run"`
+	JobState
+}
+
+type job struct {
+	name     string
+	spec     string
+	schedule Schedule
+	run      JobFunc
+
+	mu      sync.Mutex
+	running bool
+	nextRun *time.Time
+	state   JobState
+}
+
+// Scheduler runs jobs in-process on their schedules. A job never runs twice
+// at the same time, and its last run and last error are kept in the
+// settings table under "job.<name>".
+type Scheduler struct {
+	settings *repositories.SettingsRepository
+	location *time.Location
+	logger   *slog.Logger
+	jobs     map[string]*job
+	ctx      context.Context
+}
+
+func New(settings *repositories.SettingsRepository, loc *time.Location, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		settings: settings,
+		location: loc,
+		logger:   logger.With("component", "scheduler"),
+		jobs:     make(map[string]*job),
+		ctx:      context.Background(),
+	}
+}
+
+// Add registers a job. An empty spec registers a job that only runs when
+// triggered.
+func (s *Scheduler) Add(name, spec string, run JobFunc) error {
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s already registered", name)
+	}
+
+	j := &job{name: name, spec: spec, run: run}
+	if spec != "" {
+		schedule, err := Parse(spec, s.location)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", name, err)
+		}
+		j.schedule = schedule
+	}
+
+	if err := s.loadState(j); err != nil {
+		s.logger.Warn("failed to load job state", "job", name, "error", err)
+	}
+
+	s.jobs[name] = j
+	return nil
+}
+
+// Start runs every scheduled job until ctx is cancelled. A job whose run
+// was missed while the server was down, or that never ran, runs right away.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.ctx = ctx
+
+	for _, j := range s.jobs {
+		if j.schedule != nil {
+			go s.loop(ctx, j)
+		}
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	next := time.Now()
+	if lastRun := j.lastRun(); lastRun != nil {
+		next = j.schedule.Next(*lastRun)
+	}
+
+	for {
+		if next.IsZero() {
+			s.logger.Warn("job has no next run", "job", j.name)
+			return
+		}
+		j.setNextRun(next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.execute(ctx, j); errors.Is(err, ErrRunning) {
+			s.logger.Info("skipping job, previous run still going", "job", j.name)
+		}
+		next = j.schedule.Next(time.Now())
+	}
+}
+
+// Trigger starts a job now, in the background.
+func (s *Scheduler) Trigger(name string) error {
+	j, ok := s.jobs[name]
+	if !ok {
+		return ErrUnknownJob
+	}
+
+	if !j.begin() {
+		return ErrRunning
+	}
+	go s.runJob(s.ctx, j)
+
+	return nil
+}
+
+// Jobs lists all jobs by name.
+func (s *Scheduler) Jobs() []JobStatus {
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, j.status())
+	}
+
+	sort.Slice(statuses, func(a, b int) bool {
+		return statuses[a].Name < statuses[b].Name
+	})
+	return statuses
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job) error {
+	if !j.begin() {
+		return ErrRunning
+	}
+	return s.runJob(ctx, j)
+}
+
+// runJob runs a job that has already begun and records the outcome.
+func (s *Scheduler) runJob(ctx context.Context, j *job) error {
+	started := time.Now()
+	s.logger.Info("job started", "job", j.name)
+
+	err := j.run(ctx)
+
+	j.mu.Lock()
+	j.running = false
+	j.state.LastRun = &started
+	j.state.LastDuration = time.Since(started).Seconds()
+	j.state.LastError = ""
+	if err != nil {
+		j.state.LastError = err.Error()
+	} else {
+		j.state.LastSuccess = &started
+	}
+	state := j.state
+	j.mu.Unlock()
+
+	if err != nil {
+		s.logger.Warn("job failed", "job", j.name, "duration", time.Since(started), "error", err)
+	} else {
+		s.logger.Info("job finished", "job", j.name, "duration", time.Since(started))
+	}
+
+	if saveErr := s.saveState(j.name, state); saveErr != nil {
+		s.logger.Error("failed to save job state", "job", j.name, "error", saveErr)
+	}
+
+	return err
+}
+
+func (s *Scheduler) loadState(j *job) error {
+	value, ok, err := s.settings.Get(stateKey(j.name))
+	if err != nil || !ok {
+		return err
+	}
+
+	return json.Unmarshal([]byte(value), &j.state)
+}
+
+func (s *Scheduler) saveState(name string, state JobState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return s.settings.Set(stateKey(name), string(data))
+}
+
+func stateKey(name string) string {
+	return "job." + name
+}
+
+// begin marks the job running, or reports false if it already is.
+func (j *job) begin() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.running {
+		return false
+	}
+	j.running = true
+	return true
+}
+
+// lastRun returns when the job last started; a trigger may be recording a
+// run at the same time.
+func (j *job) lastRun() *time.Time {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.state.LastRun
+}
+
+func (j *job) setNextRun(next time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.nextRun = &next
+}
+
+func (j *job) status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return JobStatus{
+		Name:     j.name,
+		Schedule: j.spec,
+		Running:  j.running,
+		NextRun:  j.nextRun,
+		JobState: j.state,
+	}
+}
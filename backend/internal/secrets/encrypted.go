@@ -0,0 +1,127 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EncryptedFileSource reads secrets from a local file holding a JSON object
+// of KEY: value pairs, sealed with AES-256-GCM and base64 encoded.
+type EncryptedFileSource struct {
+	Path string
+	Key  []byte
+
+	once   sync.Once
+	values map[string]string
+	err    error
+}
+
+func NewEncryptedFileSource(path string, key []byte) *EncryptedFileSource {
+	return &EncryptedFileSource{
+		Path: path,
+		Key:  key,
+	}
+}
+
+func (e *EncryptedFileSource) Name() string {
+	return "encrypted file"
+}
+
+func (e *EncryptedFileSource) Lookup(key string) (string, bool, error) {
+	e.once.Do(e.load)
+	if e.err != nil {
+		return "", false, e.err
+	}
+
+	value, ok := e.values[key]
+	return value, ok, nil
+}
+
+func (e *EncryptedFileSource) load() {
+	data, err := os.ReadFile(e.Path)
+	if err != nil {
+		e.err = fmt.Errorf("failed to read secrets file: %w", err)
+		return
+	}
+
+	plaintext, err := Decrypt(e.Key, data)
+	if err != nil {
+		e.err = err
+		return
+	}
+
+	if err := json.Unmarshal(plaintext, &e.values); err != nil {
+		e.err = fmt.Errorf("failed to unmarshal secrets file: %w", err)
+	}
+}
+
+// ParseKey decodes a base64 encoded 32 byte key.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secrets key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, errors.New("secrets key must be 32 bytes")
+	}
+
+	return key, nil
+}
+
+// Encrypt seals plaintext into the format read by EncryptedFileSource.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+	return []byte(base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// Decrypt opens data produced by Encrypt.
+func Decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secrets file: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("secrets file is too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets file: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
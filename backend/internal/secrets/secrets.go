@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Source looks up a secret by its canonical name, e.g. CLOCKIFY_TOKEN.
+// A source that simply doesn't know the key returns ok == false and no error.
+type Source interface {
+	Name() string
+	Lookup(key string) (value string, ok bool, err error)
+}
+
+// Resolver asks its sources in order and returns the first value found.
+type Resolver struct {
+	sources []Source
+
+	mu       sync.Mutex
+	resolved map[string]string
+}
+
+func NewResolver(sources ...Source) *Resolver {
+	return &Resolver{
+		sources:  sources,
+		resolved: make(map[string]string),
+	}
+}
+
+// Get returns the value for key, or defaultValue when no source has it.
+func (r *Resolver) Get(key, defaultValue string) (string, error) {
+	for _, source := range r.sources {
+		value, ok, err := source.Lookup(key)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s from %s: %w", key, source.Name(), err)
+		}
+		if ok && value != "" {
+			return value, nil
+		}
+	}
+
+	return defaultValue, nil
+}
+
+// Secret works like Get but also remembers the value as sensitive.
+func (r *Resolver) Secret(key string) (string, error) {
+	value, err := r.Get(key, "")
+	if err != nil || value == "" {
+		return value, err
+	}
+
+	r.remember(key, value)
+	return value, nil
+}
+
+// Values returns every value resolved through Secret so far.
+func (r *Resolver) Values() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values := make([]string, 0, len(r.resolved))
+	for _, value := range r.resolved {
+		values = append(values, value)
+	}
+	return values
+}
+
+func (r *Resolver) remember(key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resolved[key] = value
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvSource reads plain environment variables.
+type EnvSource struct{}
+
+func (EnvSource) Name() string {
+	return "env"
+}
+
+func (EnvSource) Lookup(key string) (string, bool, error) {
+	value, ok := os.LookupEnv(key)
+	return value, ok, nil
+}
+
+// EnvFileSource reads the file named by the KEY_FILE variable, the
+// convention used by Docker secrets.
+type EnvFileSource struct{}
+
+func (EnvFileSource) Name() string {
+	return "env file"
+}
+
+func (EnvFileSource) Lookup(key string) (string, bool, error) {
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", false, nil
+	}
+
+	return readSecretFile(path)
+}
+
+// DirSource reads one file per secret from a directory such as /run/secrets.
+// Both KEY and key are accepted as file names.
+type DirSource struct {
+	Dir string
+}
+
+func (d DirSource) Name() string {
+	return "secrets dir"
+}
+
+func (d DirSource) Lookup(key string) (string, bool, error) {
+	if d.Dir == "" {
+		return "", false, nil
+	}
+
+	for _, name := range []string{key, strings.ToLower(key)} {
+		value, ok, err := readSecretFile(filepath.Join(d.Dir, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", false, err
+		}
+		if ok {
+			return value, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func readSecretFile(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
@@ -0,0 +1,161 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"myspace/backend/internal/interfaces"
+	"myspace/backend/internal/repositories"
+	"time"
+)
+
+// backfillAttempts is how often a month is tried before the backfill of a
+// tracker gives up; the wait doubles after each failure.
+const backfillAttempts = 4
+
+// Backfill imports history month by month, oldest first, checkpointing each
+// finished month in the Setting table so an interrupted run can resume.
+type Backfill struct {
+	engine   *Engine
+	settings *repositories.SettingsRepository
+	delay    time.Duration
+	logger   *slog.Logger
+}
+
+type backfillCheckpoint struct {
+	From      string `json:"from"`
+	Completed string `json:"completed"`
+}
+
+// NewBackfill waits delay between provider calls to stay under their rate
+// limits.
+func NewBackfill(engine *Engine, settings *repositories.SettingsRepository, delay time.Duration) *Backfill {
+	return &Backfill{
+		engine:   engine,
+		settings: settings,
+		delay:    delay,
+		logger:   engine.logger,
+	}
+}
+
+// Run backfills one tracker from the month of from up to the current month.
+// Unless restart is set, it resumes after the last checkpointed month of a
+// previous run with the same start.
+func (b *Backfill) Run(ctx context.Context, tracker interfaces.TimeTracker, from time.Time, restart bool) error {
+	source := tracker.GetSource()
+	start := b.engine.MonthStart(from)
+	end := b.engine.MonthStart(time.Now())
+
+	month := start
+	if !restart {
+		resumed, err := b.resume(source, start)
+		if err != nil {
+			return err
+		}
+		month = resumed
+	}
+
+	if month.After(end) {
+		b.logger.Info("backfill already complete", "tracker", source, "from", start.Format("2006-01"))
+		return nil
+	}
+
+	b.logger.Info("backfill starting", "tracker", source, "from", month.Format("2006-01"), "to", end.Format("2006-01"))
+
+	for ; !month.After(end); month = month.AddDate(0, 1, 0) {
+		if err := b.syncWithRetry(ctx, tracker, month); err != nil {
+			return err
+		}
+
+		checkpoint := backfillCheckpoint{From: start.Format("2006-01"), Completed: month.Format("2006-01")}
+		if err := b.saveCheckpoint(source, checkpoint); err != nil {
+			return err
+		}
+
+		if err := sleep(ctx, b.delay); err != nil {
+			return err
+		}
+	}
+
+	b.logger.Info("backfill finished", "tracker", source)
+	return nil
+}
+
+func (b *Backfill) syncWithRetry(ctx context.Context, tracker interfaces.TimeTracker, month time.Time) error {
+	wait := b.delay
+	if wait <= 0 {
+		wait = time.Second
+	}
+
+	var err error
+	for attempt := 1; attempt <= backfillAttempts; attempt++ {
+		if err = b.engine.SyncMonth(tracker, month); err == nil {
+			return nil
+		}
+
+		b.logger.Warn("backfill month failed", "tracker", tracker.GetSource(), "month", month.Format("2006-01"), "attempt", attempt, "error", err)
+		if attempt < backfillAttempts {
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+			wait *= 2
+		}
+	}
+
+	return err
+}
+
+func (b *Backfill) resume(source string, start time.Time) (time.Time, error) {
+	value, ok, err := b.settings.Get(b.checkpointKey(source))
+	if err != nil || !ok {
+		return start, err
+	}
+
+	var checkpoint backfillCheckpoint
+	if err := json.Unmarshal([]byte(value), &checkpoint); err != nil {
+		b.logger.Warn("ignoring unreadable backfill checkpoint", "tracker", source, "error", err)
+		return start, nil
+	}
+
+	if checkpoint.From != start.Format("2006-01") {
+		return start, nil
+	}
+
+	completed, err := time.ParseInLocation("2006-01", checkpoint.Completed, start.Location())
+	if err != nil {
+		return start, nil
+	}
+
+	b.logger.Info("resuming backfill", "tracker", source, "completed", checkpoint.Completed)
+	return completed.AddDate(0, 1, 0), nil
+}
+
+func (b *Backfill) saveCheckpoint(source string, checkpoint backfillCheckpoint) error {
+	value, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	return b.settings.Set(b.checkpointKey(source), string(value))
+}
+
+func (b *Backfill) checkpointKey(source string) string {
+	return "backfill." + source
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
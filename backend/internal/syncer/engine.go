@@ -0,0 +1,136 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"myspace/backend/internal/interfaces"
+	"myspace/backend/internal/repositories"
+	"myspace/backend/internal/types"
+	"sync"
+	"time"
+)
+
+// Engine copies time entries from the providers into the local Track table.
+// It works in calendar-month windows so that entries crossing a month
+// boundary are always split at the same place.
+type Engine struct {
+	trackers []interfaces.TimeTracker
+	tracks   *repositories.TracksRepository
+	location *time.Location
+	logger   *slog.Logger
+
+	mu sync.Mutex
+}
+
+func NewEngine(trackers []interfaces.TimeTracker, tracks *repositories.TracksRepository, loc *time.Location, logger *slog.Logger) *Engine {
+	return &Engine{
+		trackers: trackers,
+		tracks:   tracks,
+		location: loc,
+		logger:   logger.With("component", "sync"),
+	}
+}
+
+// Trackers returns the trackers the engine pulls from.
+func (e *Engine) Trackers() []interfaces.TimeTracker {
+	return e.trackers
+}
+
+// SyncRecent syncs the current month and the months before it.
+func (e *Engine) SyncRecent(ctx context.Context, months int) error {
+	now := time.Now().In(e.location)
+	to := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, e.location)
+	from := to.AddDate(0, -months, 0)
+
+	return e.SyncRange(ctx, e.trackers, from, to)
+}
+
+// SyncRange syncs every month touching [from, to) for the given trackers.
+// A failing tracker doesn't stop the others; all errors are returned.
+func (e *Engine) SyncRange(ctx context.Context, trackers []interfaces.TimeTracker, from, to time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var errs []error
+	for _, tracker := range trackers {
+		for month := e.MonthStart(from); month.Before(to); month = month.AddDate(0, 1, 0) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := e.syncMonth(tracker, month); err != nil {
+				errs = append(errs, err)
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SyncMonth syncs one month window of one tracker.
+func (e *Engine) SyncMonth(tracker interfaces.TimeTracker, month time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.syncMonth(tracker, e.MonthStart(month))
+}
+
+func (e *Engine) syncMonth(tracker interfaces.TimeTracker, som time.Time) error {
+	eom := som.AddDate(0, 1, 0)
+	source := tracker.GetSource()
+
+	entries, err := tracker.GetIntervals(som, eom)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s for %s: %w", source, som.Format("2006-01"), err)
+	}
+
+	if err := e.tracks.ReplaceWindow(source, som, eom, entries); err != nil {
+		return fmt.Errorf("failed to store %s for %s: %w", source, som.Format("2006-01"), err)
+	}
+
+	e.logger.Info("synced month", "tracker", source, "month", som.Format("2006-01"), "entries", len(entries))
+	return nil
+}
+
+// Apply stores one change pushed by a tracker. Entries are split at month
+// boundaries the same way syncing splits them.
+func (e *Engine) Apply(event *types.WebhookEvent) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry := event.Entry
+	var parts types.ProjectTimeList
+	if !event.Deleted && entry.Datetime != nil {
+		end := *entry.Datetime
+		if entry.End != nil {
+			end = *entry.End
+		}
+
+		for month := e.MonthStart(*entry.Datetime); !month.After(end); month = month.AddDate(0, 1, 0) {
+			if part, ok := entry.Clip(month, month.AddDate(0, 1, 0)); ok {
+				parts.Add(part)
+			}
+		}
+	}
+
+	if err := e.tracks.ReplaceEntry(event.Source, entry.EntryID, parts); err != nil {
+		return fmt.Errorf("failed to apply %s entry %s: %w", event.Source, entry.EntryID, err)
+	}
+
+	e.logger.Info("applied webhook", "tracker", event.Source, "entry", entry.EntryID, "deleted", event.Deleted, "parts", len(parts))
+	return nil
+}
+
+// Location returns the timezone sync windows are taken in.
+func (e *Engine) Location() *time.Location {
+	return e.location
+}
+
+// MonthStart returns the first instant of t's month in the sync timezone.
+func (e *Engine) MonthStart(t time.Time) time.Time {
+	t = t.In(e.location)
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, e.location)
+}
@@ -0,0 +1,166 @@
+package trackers
+
+import (
+	"encoding/json"
+	"fmt"
+	"myspace/backend/internal/cache"
+	"myspace/backend/internal/interfaces"
+	"myspace/backend/internal/types"
+	"time"
+)
+
+// CacheTTLs decides how long each kind of answer is kept. Zero means
+// forever.
+type CacheTTLs struct {
+	Running time.Duration // running timers
+	Current time.Duration // ranges that include today or the future
+	Past    time.Duration // finished ranges within the current month
+	Closed  time.Duration // ranges that ended before the current month
+	UserID  time.Duration
+}
+
+// Cached remembers a tracker's answers in a cache store, keyed by source,
+// method and range.
+type Cached struct {
+	tracker interfaces.TimeTracker
+	store   cache.Store
+	ttls    CacheTTLs
+}
+
+func NewCached(tracker interfaces.TimeTracker, store cache.Store, ttls CacheTTLs) *Cached {
+	return &Cached{
+		tracker: tracker,
+		store:   store,
+		ttls:    ttls,
+	}
+}
+
+// CachePrefix is the key prefix of everything cached for a source.
+func CachePrefix(source string) string {
+	return "tracker:" + source + ":"
+}
+
+func (c *Cached) GetSource() string {
+	return c.tracker.GetSource()
+}
+
+func (c *Cached) GetUserID() string {
+	var userID string
+	c.remember(c.key("user"), c.ttls.UserID, &userID, func() (interface{}, error) {
+		if id := c.tracker.GetUserID(); id != "" {
+			return id, nil
+		}
+		return nil, fmt.Errorf("no user id")
+	})
+	return userID
+}
+
+func (c *Cached) GetSeconds(from, to time.Time) (int, error) {
+	var seconds int
+	err := c.remember(c.rangeKey("seconds", from, to), c.rangeTTL(from, to), &seconds, func() (interface{}, error) {
+		return c.tracker.GetSeconds(from, to)
+	})
+	return seconds, err
+}
+
+// GetRunningSeconds is derived from the cached timer so it keeps ticking.
+func (c *Cached) GetRunningSeconds() (int, error) {
+	timer, err := c.GetRunningTimer()
+	if err != nil {
+		return 0, err
+	}
+	return timer.Elapsed(time.Now()), nil
+}
+
+func (c *Cached) GetRunningTimer() (*types.RunningTimer, error) {
+	var timer *types.RunningTimer
+	err := c.remember(c.key("timer"), c.ttls.Running, &timer, func() (interface{}, error) {
+		return c.tracker.GetRunningTimer()
+	})
+	return timer, err
+}
+
+func (c *Cached) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	return c.GetTimeByProject(monthRange(dayOfMonth))
+}
+
+func (c *Cached) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	return c.GetIntervals(monthRange(dayOfMonth))
+}
+
+func (c *Cached) GetTimeByProject(from, to time.Time) (types.ProjectTimeList, error) {
+	var projectTimes types.ProjectTimeList
+	err := c.remember(c.rangeKey("projects", from, to), c.rangeTTL(from, to), &projectTimes, func() (interface{}, error) {
+		return c.tracker.GetTimeByProject(from, to)
+	})
+	return projectTimes, err
+}
+
+func (c *Cached) GetIntervals(from, to time.Time) (types.ProjectTimeList, error) {
+	var projectTimes types.ProjectTimeList
+	err := c.remember(c.rangeKey("intervals", from, to), c.rangeTTL(from, to), &projectTimes, func() (interface{}, error) {
+		return c.tracker.GetIntervals(from, to)
+	})
+	return projectTimes, err
+}
+
+// GetMonthSnapshot caches the snapshot as a whole, so every view of a month
+// shares one provider round trip per cache window.
+func (c *Cached) GetMonthSnapshot(dayOfMonth time.Time) (*types.MonthSnapshot, error) {
+	from, to := monthRange(dayOfMonth)
+	var snapshot types.MonthSnapshot
+	err := c.remember(c.rangeKey("snapshot", from, to), c.rangeTTL(from, to), &snapshot, func() (interface{}, error) {
+		return c.tracker.GetMonthSnapshot(dayOfMonth)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// remember decodes a cached value into target, or calls fetch and caches
+// its result. Errors are never cached.
+func (c *Cached) remember(key string, ttl time.Duration, target interface{}, fetch func() (interface{}, error)) error {
+	if data, ok := c.store.Get(key); ok {
+		if err := json.Unmarshal(data, target); err == nil {
+			return nil
+		}
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache value: %w", err)
+	}
+	c.store.Set(key, data, ttl)
+
+	return json.Unmarshal(data, target)
+}
+
+func (c *Cached) key(method string) string {
+	return CachePrefix(c.GetSource()) + method
+}
+
+// rangeKey includes the timezone because trackers bucket days in it.
+func (c *Cached) rangeKey(method string, from, to time.Time) string {
+	return fmt.Sprintf("%s:%d:%d:%s", c.key(method), from.Unix(), to.Unix(), from.Location())
+}
+
+func (c *Cached) rangeTTL(from, to time.Time) time.Duration {
+	now := time.Now().In(from.Location())
+	som := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch {
+	case !to.After(som):
+		return c.ttls.Closed
+	case !to.After(today):
+		return c.ttls.Past
+	default:
+		return c.ttls.Current
+	}
+}
@@ -4,31 +4,49 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"myspace/backend/internal/config"
 	"myspace/backend/internal/types"
-	"strings"
+	"strconv"
 	"time"
 )
 
 type Clockify struct {
 	client *RestClient
 	config *config.Config
+	logger *slog.Logger
 }
 
 type ClockifyTimeEntry struct {
+	ID           string `json:"id"`
+	UserID       string `json:"userId"`
+	Description  string `json:"description"`
+	ProjectID    string `json:"projectId"`
+	Project      *struct {
+		Name string `json:"name"`
+	} `json:"project"`
 	TimeInterval struct {
 		Start string `json:"start"`
 		End   string `json:"end"`
 	} `json:"timeInterval"`
 }
 
-func NewClockify(cfg *config.Config) *Clockify {
+// clockifyPageSize is the largest page the time entries endpoint returns.
+const clockifyPageSize = 1000
+
+func NewClockify(cfg *config.Config, logger *slog.Logger) *Clockify {
+	logger = logger.With("tracker", "clockify")
 	return &Clockify{
-		client: NewRestClient(),
+		client: NewRestClient(logger),
 		config: cfg,
+		logger: logger,
 	}
 }
 
+func (c *Clockify) GetSource() string {
+	return "clockify"
+}
+
 func (c *Clockify) baseURI() string {
 	return "https://api.clockify.me"
 }
@@ -47,96 +65,137 @@ func (c *Clockify) getPathWithWorkspace(path string) string {
 	return fmt.Sprintf("/api/v1/workspaces/%s%s", c.config.Clockify.WorkspaceID, path)
 }
 
-func (c *Clockify) getSecondsForTimeEntry(entry ClockifyTimeEntry) (int, error) {
+// rangeParams converts the half-open range [from, to) into the UTC
+// instants Clockify expects.
+func (c *Clockify) rangeParams(from, to time.Time) map[string]string {
+	return map[string]string{
+		"start": from.UTC().Format("2006-01-02T15:04:05Z"),
+		"end":   to.Add(-time.Second).UTC().Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func (c *Clockify) getTimeEntryInterval(entry ClockifyTimeEntry) (time.Time, time.Time, error) {
 	start, err := time.Parse(time.RFC3339, entry.TimeInterval.Start)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse start time: %w", err)
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse start time: %w", err)
 	}
 	
 	end, err := time.Parse(time.RFC3339, entry.TimeInterval.End)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse end time: %w", err)
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse end time: %w", err)
 	}
 	
-	return int(end.Sub(start).Seconds()), nil
+	return start, end, nil
 }
 
-func (c *Clockify) GetUserID() string {
-	return c.config.Clockify.UserID
+// GetIntervals returns the entries overlapping [from, to), clipped to it.
+// The query is widened by a day on each side so entries crossing either
+// boundary are not missed.
+func (c *Clockify) GetIntervals(from, to time.Time) (types.ProjectTimeList, error) {
+	var projectTimes types.ProjectTimeList
+	
+	for page := 1; ; page++ {
+		entries, err := c.getTimeEntries(from.Add(-24*time.Hour), to.Add(24*time.Hour), page)
+		if err != nil {
+			return nil, err
+		}
+		
+		for _, entry := range entries {
+			start, end, err := c.getTimeEntryInterval(entry)
+			if err != nil {
+				continue
+			}
+			
+			projectTitle := ""
+			if entry.Project != nil {
+				projectTitle = entry.Project.Name
+			}
+			
+			projectTimes.Add(types.ProjectTime{
+				Source:       "clockify",
+				ProjectID:    entry.ProjectID,
+				ProjectTitle: projectTitle,
+				Seconds:      int(end.Sub(start).Seconds()),
+				Datetime:     &start,
+				End:          &end,
+				EntryID:      entry.ID,
+				Description:  entry.Description,
+			})
+		}
+		
+		if len(entries) < clockifyPageSize {
+			break
+		}
+	}
+	
+	return projectTimes.Clip(from, to), nil
 }
 
-func (c *Clockify) GetSeconds(from, to time.Time) (int, error) {
+func (c *Clockify) getTimeEntries(from, to time.Time, page int) ([]ClockifyTimeEntry, error) {
 	path := c.getPathWithWorkspace(fmt.Sprintf("/user/%s/time-entries", c.GetUserID()))
-	params := map[string]string{
-		"start": from.Format("2006-01-02") + "T00:00:00Z",
-		"end":   to.Format("2006-01-02") + "T23:59:59Z",
-	}
+	params := c.rangeParams(from, to)
+	params["hydrated"] = "true"
+	params["page"] = strconv.Itoa(page)
+	params["page-size"] = strconv.Itoa(clockifyPageSize)
 	
 	resp, err := c.client.Get(c.baseURI(), path, c.headers(), params)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get time entries: %w", err)
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 	
 	var entries []ClockifyTimeEntry
 	if err := json.Unmarshal(body, &entries); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	
+	return entries, nil
+}
+
+func (c *Clockify) GetUserID() string {
+	return c.config.Clockify.UserID
+}
+
+func (c *Clockify) GetSeconds(from, to time.Time) (int, error) {
+	intervals, err := c.GetIntervals(from, to)
+	if err != nil {
+		return 0, err
 	}
 	
 	totalSeconds := 0
-	for _, entry := range entries {
-		seconds, err := c.getSecondsForTimeEntry(entry)
-		if err != nil {
-			continue
-		}
-		totalSeconds += seconds
+	for _, interval := range intervals {
+		totalSeconds += interval.Seconds
 	}
 	
 	return totalSeconds, nil
 }
 
 func (c *Clockify) GetRunningSeconds() (int, error) {
-	return 0, nil
-}
-
-func (c *Clockify) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
-	som := time.Date(dayOfMonth.Year(), dayOfMonth.Month(), 1, 0, 0, 0, 0, dayOfMonth.Location())
-	eom := som.AddDate(0, 1, -1)
-	
-	seconds, err := c.GetSeconds(som, eom)
+	timer, err := c.GetRunningTimer()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 	
-	var projectTimes types.ProjectTimeList
-	projectTimes.Add(types.ProjectTime{
-		Source:       "clockify",
-		ProjectID:    "x",
-		ProjectTitle: c.generateRandomString(10),
-		Seconds:      seconds,
-	})
-	
-	return projectTimes, nil
+	return timer.Elapsed(time.Now()), nil
 }
 
-func (c *Clockify) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList, error) {
-	som := time.Date(dayOfMonth.Year(), dayOfMonth.Month(), 1, 0, 0, 0, 0, dayOfMonth.Location())
-	eom := som.AddDate(0, 1, -1)
-	
+// GetRunningTimer returns the in-progress entry, or nil when none is
+// running. Such entries have no end, so GetIntervals skips them.
+func (c *Clockify) GetRunningTimer() (*types.RunningTimer, error) {
 	path := c.getPathWithWorkspace(fmt.Sprintf("/user/%s/time-entries", c.GetUserID()))
 	params := map[string]string{
-		"start": som.Format("2006-01-02") + "T00:00:00Z",
-		"end":   eom.Format("2006-01-02") + "T23:59:59Z",
+		"in-progress": "true",
+		"hydrated":    "true",
 	}
 	
 	resp, err := c.client.Get(c.baseURI(), path, c.headers(), params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get time entries: %w", err)
+		return nil, fmt.Errorf("failed to get running time entry: %w", err)
 	}
 	defer resp.Body.Close()
 	
@@ -149,31 +208,47 @@ func (c *Clockify) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeLis
 	if err := json.Unmarshal(body, &entries); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
 	
-	var projectTimes types.ProjectTimeList
-	for _, entry := range entries {
-		seconds, err := c.getSecondsForTimeEntry(entry)
-		if err != nil {
-			continue
-		}
-		
-		start, err := time.Parse(time.RFC3339, entry.TimeInterval.Start)
-		if err != nil {
-			continue
-		}
-		
-		projectTimes.Add(types.ProjectTime{
-			Source:       "x",
-			ProjectID:    "x", 
-			ProjectTitle: "x",
-			Seconds:      seconds,
-			Datetime:     &start,
-		})
+	entry := entries[0]
+	startedAt, err := time.Parse(time.RFC3339, entry.TimeInterval.Start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse start time: %w", err)
+	}
+	
+	projectTitle := ""
+	if entry.Project != nil {
+		projectTitle = entry.Project.Name
+	}
+	
+	return &types.RunningTimer{
+		Source:       "clockify",
+		ProjectID:    entry.ProjectID,
+		ProjectTitle: projectTitle,
+		Description:  entry.Description,
+		StartedAt:    startedAt,
+	}, nil
+}
+
+func (c *Clockify) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	return c.GetTimeByProject(monthRange(dayOfMonth))
+}
+
+func (c *Clockify) GetTimeByProject(from, to time.Time) (types.ProjectTimeList, error) {
+	intervals, err := c.GetIntervals(from, to)
+	if err != nil {
+		return nil, err
 	}
 	
-	return projectTimes, nil
+	return intervals.GroupByProject(), nil
 }
 
-func (c *Clockify) generateRandomString(length int) string {
-	return strings.Repeat("x", length)
-}
\ No newline at end of file
+func (c *Clockify) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	return c.GetIntervals(monthRange(dayOfMonth))
+}
+
+func (c *Clockify) GetMonthSnapshot(dayOfMonth time.Time) (*types.MonthSnapshot, error) {
+	return monthSnapshot(c, dayOfMonth)
+}
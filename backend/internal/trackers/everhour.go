@@ -4,26 +4,45 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"myspace/backend/internal/config"
 	"myspace/backend/internal/types"
 	"strconv"
+	"sync"
 	"time"
 )
 
 type Everhour struct {
-	client *RestClient
-	config *config.Config
-	userID *int
+	client   *RestClient
+	config   *config.Config
+	logger   *slog.Logger
+	userID   *int
+	
+	mu       sync.Mutex
+	projects map[string]string
 }
 
 type EverhourTimeEntry struct {
+	ID        int    `json:"id"`
 	Time      int    `json:"time"`
+	Date      string `json:"date"`
 	CreatedAt string `json:"createdAt"`
+	Task      *struct {
+		ID       string   `json:"id"`
+		Name     string   `json:"name"`
+		Projects []string `json:"projects"`
+	} `json:"task"`
 }
 
 type EverhourTimer struct {
 	Status   string `json:"status"`
 	Duration int    `json:"duration"`
+	Comment  string `json:"comment"`
+	Task     *struct {
+		ID       string   `json:"id"`
+		Name     string   `json:"name"`
+		Projects []string `json:"projects"`
+	} `json:"task"`
 }
 
 type EverhourUser struct {
@@ -31,16 +50,23 @@ type EverhourUser struct {
 }
 
 type EverhourProject struct {
+	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
-func NewEverhour(cfg *config.Config) *Everhour {
+func NewEverhour(cfg *config.Config, logger *slog.Logger) *Everhour {
+	logger = logger.With("tracker", "everhour")
 	return &Everhour{
-		client: NewRestClient(),
+		client: NewRestClient(logger),
 		config: cfg,
+		logger: logger,
 	}
 }
 
+func (e *Everhour) GetSource() string {
+	return "everhour"
+}
+
 func (e *Everhour) baseURI() string {
 	return "https://api.everhour.com"
 }
@@ -76,11 +102,17 @@ func (e *Everhour) GetUserID() string {
 	return strconv.Itoa(user.ID)
 }
 
-func (e *Everhour) GetSeconds(from, to time.Time) (int, error) {
-	params := map[string]string{
+// rangeParams converts the half-open range [from, to) into the inclusive
+// calendar dates Everhour expects, taken in the range's timezone.
+func (e *Everhour) rangeParams(from, to time.Time) map[string]string {
+	return map[string]string{
 		"from": from.Format("2006-01-02"),
-		"to":   to.Format("2006-01-02"),
+		"to":   to.In(from.Location()).Add(-time.Nanosecond).Format("2006-01-02"),
 	}
+}
+
+func (e *Everhour) GetSeconds(from, to time.Time) (int, error) {
+	params := e.rangeParams(from, to)
 	
 	path := fmt.Sprintf("/users/%s/time", e.GetUserID())
 	resp, err := e.client.Get(e.baseURI(), path, e.headers(), params)
@@ -108,37 +140,66 @@ func (e *Everhour) GetSeconds(from, to time.Time) (int, error) {
 }
 
 func (e *Everhour) GetRunningSeconds() (int, error) {
+	timer, err := e.GetRunningTimer()
+	if err != nil {
+		return 0, err
+	}
+	
+	return timer.Elapsed(time.Now()), nil
+}
+
+// GetRunningTimer returns the active timer, or nil when none is running.
+// The start is derived from the reported duration, which avoids guessing
+// the timezone of Everhour's startedAt.
+func (e *Everhour) GetRunningTimer() (*types.RunningTimer, error) {
 	resp, err := e.client.Get(e.baseURI(), "/timers/current", e.headers(), nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get current timer: %w", err)
+		return nil, fmt.Errorf("failed to get current timer: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 	
 	var timer EverhourTimer
 	if err := json.Unmarshal(body, &timer); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	
 	if timer.Status != "active" {
-		return 0, nil
+		return nil, nil
+	}
+	
+	running := &types.RunningTimer{
+		Source:      "everhour",
+		Description: timer.Comment,
+		StartedAt:   time.Now().Add(-time.Duration(timer.Duration) * time.Second),
+	}
+	if timer.Task != nil {
+		if running.Description == "" {
+			running.Description = timer.Task.Name
+		}
+		if len(timer.Task.Projects) > 0 {
+			running.ProjectID = timer.Task.Projects[0]
+			running.ProjectTitle = e.getProjectName(running.ProjectID)
+		}
 	}
 	
-	return timer.Duration, nil
+	return running, nil
 }
 
 func (e *Everhour) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList, error) {
-	som := time.Date(dayOfMonth.Year(), dayOfMonth.Month(), 1, 0, 0, 0, 0, dayOfMonth.Location())
-	eom := som.AddDate(0, 1, -1)
-	
-	params := map[string]string{
-		"from": som.Format("2006-01-02"),
-		"to":   eom.Format("2006-01-02"),
-	}
+	return e.GetIntervals(monthRange(dayOfMonth))
+}
+
+func (e *Everhour) GetMonthSnapshot(dayOfMonth time.Time) (*types.MonthSnapshot, error) {
+	return monthSnapshot(e, dayOfMonth)
+}
+
+func (e *Everhour) GetIntervals(from, to time.Time) (types.ProjectTimeList, error) {
+	params := e.rangeParams(from, to)
 	
 	path := fmt.Sprintf("/users/%s/time", e.GetUserID())
 	resp, err := e.client.Get(e.baseURI(), path, e.headers(), params)
@@ -159,66 +220,106 @@ func (e *Everhour) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeLis
 	
 	var projectTimes types.ProjectTimeList
 	for _, entry := range entries {
-		createdAt, err := time.Parse(time.RFC3339, entry.CreatedAt)
+		day, err := e.entryDay(entry, from.Location())
 		if err != nil {
 			continue
 		}
 		
+		projectID := e.entryProjectID(entry)
+		description := ""
+		if entry.Task != nil {
+			description = entry.Task.Name
+		}
+		
 		projectTimes.Add(types.ProjectTime{
-			Source:       "x",
-			ProjectID:    "x",
-			ProjectTitle: "x",
+			Source:       "everhour",
+			ProjectID:    projectID,
+			ProjectTitle: e.getProjectName(projectID),
 			Seconds:      entry.Time,
-			Datetime:     &createdAt,
+			Datetime:     &day,
+			EntryID:      strconv.Itoa(entry.ID),
+			Description:  description,
 		})
 	}
 	
 	return projectTimes, nil
 }
 
+// entryDay returns the calendar day time was logged for. Everhour reports a
+// plain date in the user's timezone; createdAt is only a fallback.
+func (e *Everhour) entryDay(entry EverhourTimeEntry, loc *time.Location) (time.Time, error) {
+	if entry.Date != "" {
+		return time.ParseInLocation("2006-01-02", entry.Date, loc)
+	}
+	return time.Parse(time.RFC3339, entry.CreatedAt)
+}
+
 func (e *Everhour) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
-	som := time.Date(dayOfMonth.Year(), dayOfMonth.Month(), 1, 0, 0, 0, 0, dayOfMonth.Location())
-	eom := som.AddDate(0, 1, -1)
-	
-	seconds, err := e.GetSeconds(som, eom)
+	return e.GetTimeByProject(monthRange(dayOfMonth))
+}
+
+func (e *Everhour) GetTimeByProject(from, to time.Time) (types.ProjectTimeList, error) {
+	intervals, err := e.GetIntervals(from, to)
 	if err != nil {
 		return nil, err
 	}
 	
-	var projectTimes types.ProjectTimeList
-	if seconds > 0 {
-		projectName, _ := e.getProjectName("one-and-only")
-		projectTimes.Add(types.ProjectTime{
-			Source:       "everhour",
-			ProjectID:    "one-and-only",
-			ProjectTitle: projectName,
-			Seconds:      seconds,
-		})
+	return intervals.GroupByProject(), nil
+}
+
+func (e *Everhour) entryProjectID(entry EverhourTimeEntry) string {
+	if entry.Task == nil || len(entry.Task.Projects) == 0 {
+		return ""
+	}
+	return entry.Task.Projects[0]
+}
+
+// getProjectName looks project names up from /projects, refreshing the
+// cached list when an unknown project shows up.
+func (e *Everhour) getProjectName(projectID string) string {
+	if projectID == "" {
+		return ""
 	}
 	
-	return projectTimes, nil
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	
+	if name, ok := e.projects[projectID]; ok {
+		return name
+	}
+	
+	if err := e.loadProjects(); err != nil {
+		e.logger.Warn("failed to load projects", "error", err)
+		return ""
+	}
+	
+	// Remember unknown projects too so they don't trigger another reload
+	name := e.projects[projectID]
+	e.projects[projectID] = name
+	return name
 }
 
-func (e *Everhour) getProjectName(projectID string) (string, error) {
+func (e *Everhour) loadProjects() error {
 	resp, err := e.client.Get(e.baseURI(), "/projects", e.headers(), nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to get projects: %w", err)
+		return fmt.Errorf("failed to get projects: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
 	
 	var projects []EverhourProject
 	if err := json.Unmarshal(body, &projects); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	
-	if len(projects) > 0 {
-		return projects[0].Name, nil
+	e.projects = make(map[string]string, len(projects))
+	for _, project := range projects {
+		e.projects[project.ID] = project.Name
 	}
 	
-	return "Unknown Project", nil
-}
\ No newline at end of file
+	return nil
+}
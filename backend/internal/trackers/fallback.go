@@ -0,0 +1,111 @@
+package trackers
+
+import (
+	"log/slog"
+	"myspace/backend/internal/interfaces"
+	"myspace/backend/internal/types"
+	"sync"
+	"time"
+)
+
+// SnapshotStore keeps the last successful month snapshot of each tracker.
+type SnapshotStore interface {
+	SaveSnapshot(som time.Time, snapshot *types.MonthSnapshot) error
+	LatestSnapshot(source string, som time.Time) (*types.MonthSnapshot, bool, error)
+}
+
+// Fallback remembers every month snapshot a tracker returns and serves the
+// last one, marked stale, when the tracker fails.
+type Fallback struct {
+	tracker interfaces.TimeTracker
+	store   SnapshotStore
+	logger  *slog.Logger
+	mu      sync.Mutex
+	saved   map[string]time.Time
+}
+
+func NewFallback(tracker interfaces.TimeTracker, store SnapshotStore, logger *slog.Logger) *Fallback {
+	return &Fallback{
+		tracker: tracker,
+		store:   store,
+		logger:  logger.With("tracker", tracker.GetSource()),
+		saved:   make(map[string]time.Time),
+	}
+}
+
+func (f *Fallback) GetSource() string {
+	return f.tracker.GetSource()
+}
+
+func (f *Fallback) GetUserID() string {
+	return f.tracker.GetUserID()
+}
+
+func (f *Fallback) GetSeconds(from, to time.Time) (int, error) {
+	return f.tracker.GetSeconds(from, to)
+}
+
+func (f *Fallback) GetRunningSeconds() (int, error) {
+	return f.tracker.GetRunningSeconds()
+}
+
+func (f *Fallback) GetRunningTimer() (*types.RunningTimer, error) {
+	return f.tracker.GetRunningTimer()
+}
+
+func (f *Fallback) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	return f.tracker.GetMonthlyTimeByProject(dayOfMonth)
+}
+
+func (f *Fallback) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	return f.tracker.GetMonthIntervals(dayOfMonth)
+}
+
+func (f *Fallback) GetTimeByProject(from, to time.Time) (types.ProjectTimeList, error) {
+	return f.tracker.GetTimeByProject(from, to)
+}
+
+func (f *Fallback) GetIntervals(from, to time.Time) (types.ProjectTimeList, error) {
+	return f.tracker.GetIntervals(from, to)
+}
+
+func (f *Fallback) GetMonthSnapshot(dayOfMonth time.Time) (*types.MonthSnapshot, error) {
+	som, _ := monthRange(dayOfMonth)
+
+	snapshot, err := f.tracker.GetMonthSnapshot(dayOfMonth)
+	if err == nil {
+		f.save(som, snapshot)
+		return snapshot, nil
+	}
+
+	last, ok, loadErr := f.store.LatestSnapshot(f.GetSource(), som)
+	if loadErr != nil {
+		f.logger.Error("failed to load last snapshot", "month", som.Format("2006-01"), "error", loadErr)
+		return nil, err
+	}
+	if !ok {
+		return nil, err
+	}
+
+	f.logger.Warn("serving stale snapshot", "month", som.Format("2006-01"), "fetched_at", last.FetchedAt, "error", err)
+	last.Stale = true
+	return last, nil
+}
+
+// save stores a snapshot unless it was already stored, which is the case
+// whenever a cached snapshot is served again.
+func (f *Fallback) save(som time.Time, snapshot *types.MonthSnapshot) {
+	key := som.Format("2006-01") + ":" + som.Location().String()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if saved, ok := f.saved[key]; ok && saved.Equal(snapshot.FetchedAt) {
+		return
+	}
+	if err := f.store.SaveSnapshot(som, snapshot); err != nil {
+		f.logger.Error("failed to save snapshot", "month", som.Format("2006-01"), "error", err)
+		return
+	}
+	f.saved[key] = snapshot.FetchedAt
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"myspace/backend/internal/config"
 	"myspace/backend/internal/types"
 	"strconv"
@@ -14,6 +14,7 @@ import (
 type Mayven struct {
 	client *RestClient
 	config *config.Config
+	logger *slog.Logger
 	userID *int
 }
 
@@ -60,21 +61,24 @@ type MayvenAggregatedData struct {
 
 func (m *MayvenAggregatedData) UnmarshalJSON(data []byte) error {
 	aux := struct {
-		ItemIDInt  int    `json:"item_id"`
-		ItemIDStr  string `json:"item_id"`
-		Title      string `json:"title"`
-		SecondsStr string `json:"seconds"`
+		ItemID     interface{} `json:"item_id"`
+		Title      string      `json:"title"`
+		SecondsStr string      `json:"seconds"`
 	}{}
 	
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
 	
-	// Handle item_id as either string or int
-	if aux.ItemIDStr != "" {
-		m.ItemID = aux.ItemIDStr
-	} else {
-		m.ItemID = strconv.Itoa(aux.ItemIDInt)
+	// item_id comes as a string or a number, and as null for time outside
+	// any project
+	switch id := aux.ItemID.(type) {
+	case string:
+		m.ItemID = id
+	case float64:
+		m.ItemID = strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		m.ItemID = ""
 	}
 	
 	m.Title = aux.Title
@@ -104,13 +108,19 @@ type MayvenTimer struct {
 	} `json:"data"`
 }
 
-func NewMayven(cfg *config.Config) *Mayven {
+func NewMayven(cfg *config.Config, logger *slog.Logger) *Mayven {
+	logger = logger.With("tracker", "mayven")
 	return &Mayven{
-		client: NewRestClient(),
+		client: NewRestClient(logger),
 		config: cfg,
+		logger: logger,
 	}
 }
 
+func (m *Mayven) GetSource() string {
+	return "mayven"
+}
+
 func (m *Mayven) baseURI() string {
 	return m.config.Mayven.ApiURL
 }
@@ -122,78 +132,70 @@ func (m *Mayven) headers() map[string]string {
 	}
 }
 
+// rangeParams converts the half-open range [from, to) into the naive
+// timestamps Mayven expects. Mayven reads the range as UTC, so it is
+// converted from the range's timezone first. The days it answers with are
+// plain dates and are taken as days in the range's timezone again, see
+// GetIntervals.
+func (m *Mayven) rangeParams(from, to time.Time) map[string]string {
+	return map[string]string{
+		"dateStart": from.UTC().Format("2006-01-02 15:04:05"),
+		"dateEnd":   to.Add(-time.Second).UTC().Format("2006-01-02 15:04:05"),
+	}
+}
+
 func (m *Mayven) GetUserID() string {
 	if m.userID != nil {
-		log.Printf("[MAYVEN DEBUG] Using cached user ID: %d", *m.userID)
 		return strconv.Itoa(*m.userID)
 	}
 	
-	log.Printf("[MAYVEN DEBUG] Fetching user ID from /api/hydrate")
-	
 	resp, err := m.client.Get(m.baseURI(), "/api/hydrate", m.headers(), nil)
 	if err != nil {
-		log.Printf("[MAYVEN DEBUG] Hydrate API error: %v", err)
+		m.logger.Warn("failed to fetch user id", "error", err)
 		return ""
 	}
 	defer resp.Body.Close()
 	
-	log.Printf("[MAYVEN DEBUG] Hydrate response status: %d", resp.StatusCode)
-	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("[MAYVEN DEBUG] Error reading hydrate response: %v", err)
+		m.logger.Warn("failed to read hydrate response", "error", err)
 		return ""
 	}
 	
-	log.Printf("[MAYVEN DEBUG] Hydrate response body: %s", string(body))
-	
 	var hydrate MayvenHydrate
 	if err := json.Unmarshal(body, &hydrate); err != nil {
-		log.Printf("[MAYVEN DEBUG] Error unmarshaling hydrate response: %v", err)
+		m.logger.Warn("failed to unmarshal hydrate response", "status", resp.StatusCode, "error", err)
 		return ""
 	}
 	
 	m.userID = &hydrate.Data.Me.Data.ID
-	log.Printf("[MAYVEN DEBUG] Got user ID: %d", *m.userID)
+	m.logger.Debug("resolved user id", "user_id", *m.userID)
 	return strconv.Itoa(*m.userID)
 }
 
 func (m *Mayven) GetSeconds(from, to time.Time) (int, error) {
 	userID := m.GetUserID()
-	log.Printf("[MAYVEN DEBUG] GetUserID returned: '%s'", userID)
-	
 	if userID == "" {
-		log.Printf("[MAYVEN DEBUG] No user ID, returning 0 seconds")
+		m.logger.Warn("no user id, skipping time statistics")
 		return 0, nil
 	}
 	
-	params := map[string]string{
-		"dateStart": from.Format("2006-01-02") + " 00:00:00",
-		"dateEnd":   to.Format("2006-01-02") + " 23:59:59",
-		"users[]":   userID,
-	}
-	
-	log.Printf("[MAYVEN DEBUG] API call params: %+v", params)
+	params := m.rangeParams(from, to)
+	params["users[]"] = userID
 	
 	resp, err := m.client.Get(m.baseURI(), "/api/time-statistics", m.headers(), params)
 	if err != nil {
-		log.Printf("[MAYVEN DEBUG] API request failed: %v", err)
 		return 0, fmt.Errorf("failed to get time statistics: %w", err)
 	}
 	defer resp.Body.Close()
 	
-	log.Printf("[MAYVEN DEBUG] API response status: %d", resp.StatusCode)
-	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return 0, fmt.Errorf("failed to read response body: %w", err)
 	}
 	
-	log.Printf("[MAYVEN DEBUG] API response body: %s", string(body))
-	
 	var stats MayvenTimeStats
 	if err := json.Unmarshal(body, &stats); err != nil {
-		log.Printf("[MAYVEN DEBUG] JSON unmarshal error: %v", err)
 		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	
@@ -202,49 +204,77 @@ func (m *Mayven) GetSeconds(from, to time.Time) (int, error) {
 		totalSeconds += item.Seconds
 	}
 	
-	log.Printf("[MAYVEN DEBUG] Chart data items: %d, total seconds: %d", len(stats.Data.ChartData), totalSeconds)
+	m.logger.Debug("time statistics", "chart_items", len(stats.Data.ChartData), "seconds", totalSeconds)
 	return totalSeconds, nil
 }
 
 func (m *Mayven) GetRunningSeconds() (int, error) {
+	timer, err := m.GetRunningTimer()
+	if err != nil {
+		return 0, err
+	}
+	
+	return timer.Elapsed(time.Now()), nil
+}
+
+// GetRunningTimer returns the running timer, or nil when none is running.
+// Mayven's timer doesn't say which project it is for.
+func (m *Mayven) GetRunningTimer() (*types.RunningTimer, error) {
 	resp, err := m.client.Get(m.baseURI(), "/api/timer", m.headers(), nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get timer: %w", err)
+		return nil, fmt.Errorf("failed to get timer: %w", err)
 	}
 	defer resp.Body.Close()
 	
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 	
 	var timer MayvenTimer
 	if err := json.Unmarshal(body, &timer); err != nil {
-		return 0, nil
+		return nil, nil
 	}
 	
 	if timer.Data.StartedAt == "" {
-		return 0, nil
+		return nil, nil
 	}
 	
 	startedAt, err := time.Parse(time.RFC3339, timer.Data.StartedAt)
 	if err != nil {
-		return 0, nil
+		return nil, nil
 	}
 	
-	return int(time.Since(startedAt).Seconds()), nil
+	return &types.RunningTimer{
+		Source:    "mayven",
+		StartedAt: startedAt,
+	}, nil
 }
 
 func (m *Mayven) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList, error) {
-	som := time.Date(dayOfMonth.Year(), dayOfMonth.Month(), 1, 0, 0, 0, 0, dayOfMonth.Location())
-	eom := som.AddDate(0, 1, -1)
+	return m.GetIntervals(monthRange(dayOfMonth))
+}
+
+// GetMonthSnapshot needs a second call for the project breakdown because
+// Mayven's daily chart carries no projects.
+func (m *Mayven) GetMonthSnapshot(dayOfMonth time.Time) (*types.MonthSnapshot, error) {
+	snapshot, err := monthSnapshot(m, dayOfMonth)
+	if err != nil {
+		return nil, err
+	}
 	
-	params := map[string]string{
-		"dateStart": som.Format("2006-01-02") + " 00:00:00",
-		"dateEnd":   eom.Format("2006-01-02") + " 23:59:59",
-		"users[]":   m.GetUserID(),
+	snapshot.Projects, err = m.GetTimeByProject(snapshot.From, snapshot.To)
+	if err != nil {
+		return nil, err
 	}
 	
+	return snapshot, nil
+}
+
+func (m *Mayven) GetIntervals(from, to time.Time) (types.ProjectTimeList, error) {
+	params := m.rangeParams(from, to)
+	params["users[]"] = m.GetUserID()
+	
 	resp, err := m.client.Get(m.baseURI(), "/api/time-statistics", m.headers(), params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get time statistics: %w", err)
@@ -261,19 +291,22 @@ func (m *Mayven) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList,
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 	
+	// The chart's days carry no timezone; they are read as midnight in the
+	// range's timezone so each day's total lands on the same calendar date
 	var projectTimes types.ProjectTimeList
 	for _, item := range stats.Data.ChartData {
-		date, err := time.Parse("2006-01-02", item.Date)
+		date, err := time.ParseInLocation("2006-01-02", item.Date, from.Location())
 		if err != nil {
 			continue
 		}
 		
 		projectTimes.Add(types.ProjectTime{
-			Source:       "x",
-			ProjectID:    "x",
-			ProjectTitle: "x",
+			Source:       "mayven",
+			ProjectID:    "",
+			ProjectTitle: "",
 			Seconds:      item.Seconds,
 			Datetime:     &date,
+			EntryID:      item.Date,
 		})
 	}
 	
@@ -281,18 +314,16 @@ func (m *Mayven) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList,
 }
 
 func (m *Mayven) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
-	som := time.Date(dayOfMonth.Year(), dayOfMonth.Month(), 1, 0, 0, 0, 0, dayOfMonth.Location())
-	eom := som.AddDate(0, 1, -1)
-	
-	params := map[string]string{
-		"dateStart":            som.Format("2006-01-02") + " 00:00:00",
-		"dateEnd":              eom.Format("2006-01-02") + " 23:59:59",
-		"users[]":              m.GetUserID(),
-		"groupByPrimaryValue":  "project_id",
-		"groupBySecondValue":   "todo_id",
-		"groupBy":              "project_id",
-		"orderBy":              "seconds:desc",
-	}
+	return m.GetTimeByProject(monthRange(dayOfMonth))
+}
+
+func (m *Mayven) GetTimeByProject(from, to time.Time) (types.ProjectTimeList, error) {
+	params := m.rangeParams(from, to)
+	params["users[]"] = m.GetUserID()
+	params["groupByPrimaryValue"] = "project_id"
+	params["groupBySecondValue"] = "todo_id"
+	params["groupBy"] = "project_id"
+	params["orderBy"] = "seconds:desc"
 	
 	resp, err := m.client.Get(m.baseURI(), "/api/time-statistics", m.headers(), params)
 	if err != nil {
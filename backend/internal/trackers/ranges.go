@@ -0,0 +1,33 @@
+package trackers
+
+import (
+	"myspace/backend/internal/interfaces"
+	"myspace/backend/internal/types"
+	"time"
+)
+
+// monthRange returns the half-open range [som, eom) of the month containing
+// dayOfMonth, in dayOfMonth's timezone.
+func monthRange(dayOfMonth time.Time) (time.Time, time.Time) {
+	som := time.Date(dayOfMonth.Year(), dayOfMonth.Month(), 1, 0, 0, 0, 0, dayOfMonth.Location())
+	return som, som.AddDate(0, 1, 0)
+}
+
+// monthSnapshot builds a month snapshot from a single intervals call,
+// deriving the project breakdown from the intervals.
+func monthSnapshot(tracker interfaces.TimeTracker, dayOfMonth time.Time) (*types.MonthSnapshot, error) {
+	from, to := monthRange(dayOfMonth)
+	intervals, err := tracker.GetIntervals(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MonthSnapshot{
+		Source:    tracker.GetSource(),
+		From:      from,
+		To:        to,
+		Intervals: intervals,
+		Projects:  intervals.GroupByProject(),
+		FetchedAt: time.Now(),
+	}, nil
+}
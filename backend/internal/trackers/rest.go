@@ -1,18 +1,26 @@
 package trackers
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"myspace/backend/internal/logging"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type RestClient struct {
 	client *http.Client
+	logger *slog.Logger
 }
 
-func NewRestClient() *RestClient {
+func NewRestClient(logger *slog.Logger) *RestClient {
 	return &RestClient{
 		client: &http.Client{},
+		logger: logger,
 	}
 }
 
@@ -42,10 +50,39 @@ func (r *RestClient) Get(baseURI, path string, headers map[string]string, params
 		req.Header.Set(key, value)
 	}
 	
+	r.logger.Debug("http request", "method", req.Method, "url", fullURL, logging.Headers(req.Header))
+	
+	started := time.Now()
 	resp, err := r.client.Do(req)
 	if err != nil {
+		r.logger.Warn("http request failed", "method", req.Method, "url", fullURL, "error", err)
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	
+	r.logger.Info("http response", "method", req.Method, "path", path, "status", resp.StatusCode, "duration", time.Since(started))
+	
+	if r.logger.Enabled(context.Background(), slog.LevelDebug) {
+		r.logBody(resp)
+	}
+	
+	// Outages and rate limits often come with a JSON body that would decode
+	// into an empty result, so they are reported as errors instead.
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		return nil, fmt.Errorf("provider unavailable: %s", resp.Status)
+	}
+	
 	return resp, nil
-}
\ No newline at end of file
+}
+
+// logBody logs the response body and rewinds it for the caller.
+func (r *RestClient) logBody(resp *http.Response) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		r.logger.Debug("failed to read response body", "error", err)
+	}
+	
+	r.logger.Debug("http response body", "status", resp.StatusCode, "body", string(body))
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+}
@@ -0,0 +1,81 @@
+package trackers
+
+import (
+	"myspace/backend/internal/interfaces"
+	"myspace/backend/internal/types"
+	"time"
+)
+
+// TrackStore is the local copy of synced time entries.
+type TrackStore interface {
+	GetIntervals(source string, from, to time.Time) (types.ProjectTimeList, error)
+}
+
+// Stored serves a tracker's history from the local store and only asks the
+// provider for things the store can't know, like the running timer.
+type Stored struct {
+	remote interfaces.TimeTracker
+	store  TrackStore
+}
+
+func NewStored(remote interfaces.TimeTracker, store TrackStore) *Stored {
+	return &Stored{
+		remote: remote,
+		store:  store,
+	}
+}
+
+func (s *Stored) GetSource() string {
+	return s.remote.GetSource()
+}
+
+func (s *Stored) GetUserID() string {
+	return s.remote.GetUserID()
+}
+
+func (s *Stored) GetSeconds(from, to time.Time) (int, error) {
+	intervals, err := s.GetIntervals(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	totalSeconds := 0
+	for _, interval := range intervals {
+		totalSeconds += interval.Seconds
+	}
+
+	return totalSeconds, nil
+}
+
+func (s *Stored) GetRunningSeconds() (int, error) {
+	return s.remote.GetRunningSeconds()
+}
+
+func (s *Stored) GetRunningTimer() (*types.RunningTimer, error) {
+	return s.remote.GetRunningTimer()
+}
+
+func (s *Stored) GetMonthlyTimeByProject(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	return s.GetTimeByProject(monthRange(dayOfMonth))
+}
+
+func (s *Stored) GetMonthIntervals(dayOfMonth time.Time) (types.ProjectTimeList, error) {
+	return s.GetIntervals(monthRange(dayOfMonth))
+}
+
+func (s *Stored) GetMonthSnapshot(dayOfMonth time.Time) (*types.MonthSnapshot, error) {
+	return monthSnapshot(s, dayOfMonth)
+}
+
+func (s *Stored) GetTimeByProject(from, to time.Time) (types.ProjectTimeList, error) {
+	intervals, err := s.GetIntervals(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return intervals.GroupByProject(), nil
+}
+
+func (s *Stored) GetIntervals(from, to time.Time) (types.ProjectTimeList, error) {
+	return s.store.GetIntervals(s.GetSource(), from, to)
+}
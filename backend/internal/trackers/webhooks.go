@@ -0,0 +1,174 @@
+package trackers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"myspace/backend/internal/types"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrWebhookSignature = errors.New("invalid webhook signature")
+
+// VerifyWebhook compares the Clockify-Signature header with the token
+// Clockify generated for the webhook.
+func (c *Clockify) VerifyWebhook(header http.Header, body []byte) error {
+	secret := c.config.Clockify.WebhookSecret
+	signature := header.Get("Clockify-Signature")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(signature), []byte(secret)) != 1 {
+		return ErrWebhookSignature
+	}
+	return nil
+}
+
+// ParseWebhook handles the time entry events. Entries of other users in the
+// workspace and timers that are still running are ignored.
+func (c *Clockify) ParseWebhook(header http.Header, body []byte, loc *time.Location) (*types.WebhookEvent, error) {
+	var entry ClockifyTimeEntry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook: %w", err)
+	}
+	if entry.ID == "" {
+		return nil, fmt.Errorf("webhook has no time entry id")
+	}
+	if entry.UserID != "" && entry.UserID != c.GetUserID() {
+		return nil, nil
+	}
+
+	event := &types.WebhookEvent{
+		Source: "clockify",
+		Entry:  types.ProjectTime{Source: "clockify", EntryID: entry.ID},
+	}
+
+	switch header.Get("Clockify-Webhook-Event-Type") {
+	case "TIME_ENTRY_DELETED":
+		event.Deleted = true
+		return event, nil
+	case "NEW_TIMER_STARTED":
+		return nil, nil
+	}
+
+	start, end, err := c.getTimeEntryInterval(entry)
+	if err != nil {
+		// no end yet: still running
+		return nil, nil
+	}
+	start, end = start.In(loc), end.In(loc)
+
+	projectTitle := ""
+	if entry.Project != nil {
+		projectTitle = entry.Project.Name
+	}
+	event.Entry = types.ProjectTime{
+		Source:       "clockify",
+		ProjectID:    entry.ProjectID,
+		ProjectTitle: projectTitle,
+		Seconds:      int(end.Sub(start).Seconds()),
+		Datetime:     &start,
+		End:          &end,
+		EntryID:      entry.ID,
+		Description:  entry.Description,
+	}
+
+	return event, nil
+}
+
+// EverhourWebhook is the envelope Everhour wraps hook payloads in.
+type EverhourWebhook struct {
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// VerifyWebhook checks X-Hook-Signature, the hex HMAC-SHA256 of the body
+// keyed with the hook's secret.
+func (e *Everhour) VerifyWebhook(header http.Header, body []byte) error {
+	secret := e.config.Everhour.WebhookSecret
+	if secret == "" {
+		return ErrWebhookSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(strings.ToLower(header.Get("X-Hook-Signature"))), []byte(expected)) {
+		return ErrWebhookSignature
+	}
+	return nil
+}
+
+// Handshake answers the request Everhour sends when a hook is created, which
+// carries the hook's secret in X-Hook-Secret and expects it echoed back. It
+// is only confirmed for the configured secret, so no one else gets a hook
+// accepted.
+func (e *Everhour) Handshake(header, reply http.Header) (bool, error) {
+	secret := header.Get("X-Hook-Secret")
+	if secret == "" {
+		return false, nil
+	}
+
+	configured := e.config.Everhour.WebhookSecret
+	if configured == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(configured)) != 1 {
+		return true, ErrWebhookSignature
+	}
+	reply.Set("X-Hook-Secret", secret)
+	return true, nil
+}
+
+// ParseWebhook handles api:time:* events; a removed record, or one whose
+// time dropped to zero, deletes the entry.
+func (e *Everhour) ParseWebhook(header http.Header, body []byte, loc *time.Location) (*types.WebhookEvent, error) {
+	var hook EverhourWebhook
+	if err := json.Unmarshal(body, &hook); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook: %w", err)
+	}
+	if !strings.HasPrefix(hook.Event, "api:time:") {
+		return nil, nil
+	}
+
+	var entry EverhourTimeEntry
+	if err := json.Unmarshal(hook.Payload, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal time record: %w", err)
+	}
+	if entry.ID == 0 {
+		return nil, fmt.Errorf("webhook has no time record id")
+	}
+
+	event := &types.WebhookEvent{
+		Source: "everhour",
+		Entry:  types.ProjectTime{Source: "everhour", EntryID: strconv.Itoa(entry.ID)},
+	}
+	if strings.HasSuffix(hook.Event, ":removed") || strings.HasSuffix(hook.Event, ":deleted") || entry.Time == 0 {
+		event.Deleted = true
+		return event, nil
+	}
+
+	day, err := e.entryDay(entry, loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time record date: %w", err)
+	}
+
+	projectID := e.entryProjectID(entry)
+	description := ""
+	if entry.Task != nil {
+		description = entry.Task.Name
+	}
+	event.Entry = types.ProjectTime{
+		Source:       "everhour",
+		ProjectID:    projectID,
+		ProjectTitle: e.getProjectName(projectID),
+		Seconds:      entry.Time,
+		Datetime:     &day,
+		EntryID:      strconv.Itoa(entry.ID),
+		Description:  description,
+	}
+
+	return event, nil
+}
@@ -0,0 +1,106 @@
+package types
+
+import "time"
+
+// MonthSnapshot is everything one tracker reports for a month, fetched once
+// so the day, month-to-date, calendar and project views can all be derived
+// from it without asking the provider again.
+type MonthSnapshot struct {
+	Source    string          `json:"source"`
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	Intervals ProjectTimeList `json:"intervals"`
+	Projects  ProjectTimeList `json:"projects"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Stale     bool            `json:"stale"`
+}
+
+// MonthSnapshots holds the snapshots of every tracker for the same month.
+type MonthSnapshots []*MonthSnapshot
+
+// Intervals returns the intervals of all snapshots
+func (ms MonthSnapshots) Intervals() ProjectTimeList {
+	var result ProjectTimeList
+	for _, snapshot := range ms {
+		result.Merge(snapshot.Intervals)
+	}
+	return result
+}
+
+// Projects returns the per-project totals of all snapshots
+func (ms MonthSnapshots) Projects() ProjectTimeList {
+	var result ProjectTimeList
+	for _, snapshot := range ms {
+		result.Merge(snapshot.Projects)
+	}
+	return result
+}
+
+// Hours returns the hours tracked in [from, to), which should lie within
+// the snapshots' month
+func (ms MonthSnapshots) Hours(from, to time.Time) float64 {
+	seconds := 0
+	for _, item := range ms.Intervals().Clip(from, to) {
+		seconds += item.Seconds
+	}
+	return float64(seconds) / 3600
+}
+
+// Stale lists the sources served from the last known data because their
+// provider could not be reached, with how old that data is at now. A source
+// stale in several months is listed once, with its oldest data.
+func (ms MonthSnapshots) Stale(now time.Time) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	index := make(map[string]int)
+	for _, snapshot := range ms {
+		if !snapshot.Stale {
+			continue
+		}
+		if i, ok := index[snapshot.Source]; ok {
+			if snapshot.FetchedAt.Before(result[i]["fetched_at"].(time.Time)) {
+				result[i]["fetched_at"] = snapshot.FetchedAt
+				result[i]["age_seconds"] = int(now.Sub(snapshot.FetchedAt).Seconds())
+			}
+			continue
+		}
+
+		index[snapshot.Source] = len(result)
+		result = append(result, map[string]interface{}{
+			"source":      snapshot.Source,
+			"fetched_at":  snapshot.FetchedAt,
+			"age_seconds": int(now.Sub(snapshot.FetchedAt).Seconds()),
+		})
+	}
+	return result
+}
+
+// FetchedAt returns when the most recent of the snapshots was fetched
+func (ms MonthSnapshots) FetchedAt() time.Time {
+	var latest time.Time
+	for _, snapshot := range ms {
+		if snapshot.FetchedAt.After(latest) {
+			latest = snapshot.FetchedAt
+		}
+	}
+	return latest
+}
+
+// Only keeps the snapshots of the given sources, or all of them when none
+// are given
+func (ms MonthSnapshots) Only(sources []string) MonthSnapshots {
+	if len(sources) == 0 {
+		return ms
+	}
+	selected := make(map[string]bool)
+	for _, source := range sources {
+		selected[source] = true
+	}
+
+	var result MonthSnapshots
+	for _, snapshot := range ms {
+		if selected[snapshot.Source] {
+			result = append(result, snapshot)
+		}
+	}
+	return result
+}
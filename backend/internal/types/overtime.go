@@ -0,0 +1,23 @@
+package types
+
+// OvertimeMonth is one month of the overtime ledger. Delta is actual minus
+// expected hours, and Balance carries every earlier month's delta and
+// adjustments forward.
+type OvertimeMonth struct {
+	Month       string  `json:"month"`
+	Actual      float64 `json:"actual"`
+	Expected    float64 `json:"expected"`
+	Delta       float64 `json:"delta"`
+	Adjustments float64 `json:"adjustments"`
+	Balance     float64 `json:"balance"`
+	Partial     bool    `json:"partial"`
+	Stale       bool    `json:"stale"`
+}
+
+// OvertimeLedger lists the months from the ledger's start up to a day.
+type OvertimeLedger struct {
+	Start   string          `json:"start"`
+	Until   string          `json:"until"`
+	Months  []OvertimeMonth `json:"months"`
+	Balance float64         `json:"balance"`
+}
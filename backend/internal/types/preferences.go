@@ -0,0 +1,120 @@
+package types
+
+import (
+	"fmt"
+	"myspace/backend/internal/workdays"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Preferences are the user's settings that shape goals and calendars
+type Preferences struct {
+	DailyGoal   float64 `json:"daily_goal"`
+	MonthlyGoal float64 `json:"monthly_goal"`
+	WeekStart   string  `json:"week_start"`
+	Timezone    string  `json:"timezone"`
+	Currency    string  `json:"currency"`
+
+	// WorkWeek lists the weekdays worked, e.g. "monday"
+	WorkWeek []string `json:"work_week"`
+	// HolidayCountry picks the built-in public holidays; empty for none
+	HolidayCountry string `json:"holiday_country"`
+	// VacationDays is the yearly vacation allowance; 0 when not tracked
+	VacationDays float64 `json:"vacation_days"`
+	// OvertimeStart is the first month of the overtime ledger, "2006-01";
+	// empty starts it each January
+	OvertimeStart string `json:"overtime_start"`
+	// EarningsTarget is what the month should earn, in Currency; 0 for none
+	EarningsTarget float64 `json:"earnings_target"`
+}
+
+// Validate checks every field, returning the first problem found
+func (p Preferences) Validate() error {
+	if p.DailyGoal <= 0 || p.DailyGoal > 24 {
+		return fmt.Errorf("daily_goal must be between 0 and 24 hours")
+	}
+	if p.MonthlyGoal <= 0 || p.MonthlyGoal > 31*24 {
+		return fmt.Errorf("monthly_goal must be between 0 and 744 hours")
+	}
+	if p.WeekStart != "monday" && p.WeekStart != "sunday" {
+		return fmt.Errorf("week_start must be monday or sunday")
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "" {
+		return fmt.Errorf("timezone must be an IANA timezone name")
+	}
+	if !IsCurrency(p.Currency) {
+		return fmt.Errorf("currency must be a three-letter ISO 4217 code")
+	}
+	if len(p.WorkWeek) == 0 {
+		return fmt.Errorf("work_week must list at least one weekday")
+	}
+	seen := make(map[string]bool)
+	for _, day := range p.WorkWeek {
+		if _, ok := workdays.ParseWeekday(day); !ok {
+			return fmt.Errorf("work_week contains unknown weekday %q", day)
+		}
+		if seen[day] {
+			return fmt.Errorf("work_week lists %s twice", day)
+		}
+		seen[day] = true
+	}
+	if p.VacationDays < 0 || p.VacationDays > 366 {
+		return fmt.Errorf("vacation_days must be between 0 and 366")
+	}
+	if _, err := time.Parse("2006-01", p.OvertimeStart); p.OvertimeStart != "" && err != nil {
+		return fmt.Errorf("overtime_start must be a month, YYYY-MM")
+	}
+	if p.EarningsTarget < 0 {
+		return fmt.Errorf("earnings_target must not be negative")
+	}
+	if _, ok := workdays.Countries()[p.HolidayCountry]; p.HolidayCountry != "" && !ok {
+		return fmt.Errorf("holiday_country must be one of %s", strings.Join(countryCodes(), ", "))
+	}
+	return nil
+}
+
+// Location returns the preferred timezone, or UTC if it can't be loaded
+func (p Preferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// FirstWeekday returns the day calendar rows start on
+func (p Preferences) FirstWeekday() time.Weekday {
+	if p.WeekStart == "sunday" {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// WorkWeekdays returns the worked weekdays
+func (p Preferences) WorkWeekdays() []time.Weekday {
+	days := make([]time.Weekday, 0, len(p.WorkWeek))
+	for _, day := range p.WorkWeek {
+		if weekday, ok := workdays.ParseWeekday(day); ok {
+			days = append(days, weekday)
+		}
+	}
+	return days
+}
+
+// IsCurrency reports whether code looks like an ISO 4217 currency code
+func IsCurrency(code string) bool {
+	return currencyPattern.MatchString(code)
+}
+
+func countryCodes() []string {
+	var codes []string
+	for code := range workdays.Countries() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
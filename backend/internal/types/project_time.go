@@ -11,6 +11,9 @@ type ProjectTime struct {
 	ProjectTitle string     `json:"project_title"`
 	Seconds      int        `json:"seconds"`
 	Datetime     *time.Time `json:"datetime,omitempty"`
+	End          *time.Time `json:"end,omitempty"`
+	EntryID      string     `json:"entry_id,omitempty"`
+	Description  string     `json:"description,omitempty"`
 }
 
 func (pt *ProjectTime) GetHours() float64 {
@@ -31,5 +34,85 @@ func (pt *ProjectTime) ToMap() map[string]interface{} {
 		result["datetime"] = pt.Datetime
 	}
 	
+	if pt.End != nil {
+		result["end"] = pt.End
+	}
+	
 	return result
+}
+
+// Clip returns the part of the entry that falls inside [from, to), with
+// seconds scaled by the overlap. Entries without an end are kept whole when
+// they start inside the range.
+func (pt ProjectTime) Clip(from, to time.Time) (ProjectTime, bool) {
+	if pt.Datetime == nil {
+		return pt, true
+	}
+	
+	if pt.End == nil || !pt.End.After(*pt.Datetime) {
+		inside := !pt.Datetime.Before(from) && pt.Datetime.Before(to)
+		return pt, inside
+	}
+	
+	start, end := *pt.Datetime, *pt.End
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return pt, false
+	}
+	
+	if start.Equal(*pt.Datetime) && end.Equal(*pt.End) {
+		return pt, true
+	}
+	
+	clipped := pt
+	clipped.Datetime = &start
+	clipped.End = &end
+	clipped.Seconds = pt.scaledSeconds(end.Sub(start))
+	return clipped, true
+}
+
+// SplitByDay breaks an entry that crosses midnight in loc into one entry per
+// calendar day, splitting the seconds proportionally.
+func (pt ProjectTime) SplitByDay(loc *time.Location) []ProjectTime {
+	if pt.Datetime == nil || pt.End == nil || !pt.End.After(*pt.Datetime) {
+		return []ProjectTime{pt}
+	}
+	
+	var parts []ProjectTime
+	remaining := pt.Seconds
+	
+	start := pt.Datetime.In(loc)
+	for start.Before(*pt.End) {
+		nextDay := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+		end := nextDay
+		if !end.Before(*pt.End) {
+			end = pt.End.In(loc)
+		}
+		
+		part := pt
+		partStart, partEnd := start, end
+		part.Datetime = &partStart
+		part.End = &partEnd
+		part.Seconds = pt.scaledSeconds(end.Sub(start))
+		if !end.Before(*pt.End) {
+			// The last part absorbs rounding so the parts add up exactly.
+			part.Seconds = remaining
+		}
+		remaining -= part.Seconds
+		
+		parts = append(parts, part)
+		start = end
+	}
+	
+	return parts
+}
+
+func (pt ProjectTime) scaledSeconds(part time.Duration) int {
+	total := pt.End.Sub(*pt.Datetime)
+	return int(math.Round(float64(pt.Seconds) * float64(part) / float64(total)))
 }
\ No newline at end of file
@@ -17,27 +17,41 @@ func (ptl ProjectTimeList) GetHours() float64 {
 	return math.Round(total*100) / 100
 }
 
-// GetDailyHours returns a map of daily hours for the given month
+// GetDailyHours returns a map of daily hours for the given month. Days
+// follow the location of dayOfMonth and entries crossing midnight are split
+// across the days they cover.
 func (ptl ProjectTimeList) GetDailyHours(dayOfMonth time.Time) map[string]*float64 {
 	som := time.Date(dayOfMonth.Year(), dayOfMonth.Month(), 1, 0, 0, 0, 0, dayOfMonth.Location())
-	eom := som.AddDate(0, 1, -1)
+	return ptl.GetDailyHoursBetween(som, som.AddDate(0, 1, 0))
+}
 
+// GetDailyHoursBetween returns a map of daily hours for every day in
+// [from, to), keyed by date in from's timezone
+func (ptl ProjectTimeList) GetDailyHoursBetween(from, to time.Time) map[string]*float64 {
+	loc := from.Location()
 	days := make(map[string]*float64)
 
-	// Initialize all days of the month with nil
-	for d := som; !d.After(eom); d = d.AddDate(0, 0, 1) {
+	// Initialize all days of the range with nil
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
 		days[d.Format("2006-01-02")] = nil
 	}
 
-	// Sum up hours for each day
+	// Sum up hours for each day, split at midnight for entries that span
+	// several days
 	for _, item := range ptl {
-		if item.Datetime != nil {
-			day := item.Datetime.Format("2006-01-02")
+		for _, part := range item.SplitByDay(loc) {
+			if part.Datetime == nil {
+				continue
+			}
+			day := part.Datetime.In(loc).Format("2006-01-02")
+			if _, inRange := days[day]; !inRange {
+				continue
+			}
 			if days[day] == nil {
 				hours := 0.0
 				days[day] = &hours
 			}
-			*days[day] += item.GetHours()
+			*days[day] += part.GetHours()
 		}
 	}
 
@@ -62,3 +76,61 @@ func (ptl *ProjectTimeList) Merge(other ProjectTimeList) {
 func (ptl *ProjectTimeList) Add(projectTime ProjectTime) {
 	*ptl = append(*ptl, projectTime)
 }
+
+// Clip returns the parts of all entries that fall inside [from, to)
+func (ptl ProjectTimeList) Clip(from, to time.Time) ProjectTimeList {
+	var result ProjectTimeList
+	for _, item := range ptl {
+		if clipped, ok := item.Clip(from, to); ok {
+			result.Add(clipped)
+		}
+	}
+	return result
+}
+
+// SourcesWithoutProjects lists, in order of appearance, the sources none of
+// whose entries name a project, like Mayven, which only reports daily
+// totals
+func (ptl ProjectTimeList) SourcesWithoutProjects() []string {
+	withProjects := make(map[string]bool)
+	for _, item := range ptl {
+		if item.ProjectID != "" {
+			withProjects[item.Source] = true
+		}
+	}
+
+	sources := []string{}
+	seen := make(map[string]bool)
+	for _, item := range ptl {
+		if withProjects[item.Source] || seen[item.Source] {
+			continue
+		}
+		seen[item.Source] = true
+		sources = append(sources, item.Source)
+	}
+	return sources
+}
+
+// GroupByProject sums entries per source and project, dropping their times
+func (ptl ProjectTimeList) GroupByProject() ProjectTimeList {
+	var result ProjectTimeList
+	index := make(map[string]int)
+
+	for _, item := range ptl {
+		key := item.Source + ":" + item.ProjectID
+		if i, ok := index[key]; ok {
+			result[i].Seconds += item.Seconds
+			continue
+		}
+
+		index[key] = len(result)
+		result.Add(ProjectTime{
+			Source:       item.Source,
+			ProjectID:    item.ProjectID,
+			ProjectTitle: item.ProjectTitle,
+			Seconds:      item.Seconds,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ReportDimensions are the values accepted for grouping a report
+var ReportDimensions = map[string]bool{
+	"day":     true,
+	"week":    true,
+	"month":   true,
+	"project": true,
+	"source":  true,
+}
+
+// ReportGroup is one node of a nested report
+type ReportGroup struct {
+	Key     string        `json:"key"`
+	Label   string        `json:"label"`
+	Seconds int           `json:"seconds"`
+	Hours   float64       `json:"hours"`
+	Groups  []ReportGroup `json:"groups,omitempty"`
+}
+
+// GroupBy nests the list by each dimension in turn. Time dimensions use
+// loc and entries crossing midnight are split first.
+func (ptl ProjectTimeList) GroupBy(dimensions []string, loc *time.Location) []ReportGroup {
+	var parts ProjectTimeList
+	for _, item := range ptl {
+		parts.Merge(item.SplitByDay(loc))
+	}
+
+	return parts.groupBy(dimensions, loc)
+}
+
+func (ptl ProjectTimeList) groupBy(dimensions []string, loc *time.Location) []ReportGroup {
+	if len(dimensions) == 0 {
+		return nil
+	}
+
+	dimension := dimensions[0]
+	buckets := make(map[string]ProjectTimeList)
+	labels := make(map[string]string)
+
+	for _, item := range ptl {
+		key, label := item.reportKey(dimension, loc)
+		buckets[key] = append(buckets[key], item)
+		labels[key] = label
+	}
+
+	groups := make([]ReportGroup, 0, len(buckets))
+	for key, items := range buckets {
+		seconds := 0
+		for _, item := range items {
+			seconds += item.Seconds
+		}
+
+		groups = append(groups, ReportGroup{
+			Key:     key,
+			Label:   labels[key],
+			Seconds: seconds,
+			Hours:   math.Round(float64(seconds)/3600*100) / 100,
+			Groups:  items.groupBy(dimensions[1:], loc),
+		})
+	}
+
+	// Periods read chronologically, projects and sources largest first
+	sort.Slice(groups, func(i, j int) bool {
+		if dimension == "project" || dimension == "source" {
+			if groups[i].Seconds != groups[j].Seconds {
+				return groups[i].Seconds > groups[j].Seconds
+			}
+		}
+		return groups[i].Key < groups[j].Key
+	})
+
+	return groups
+}
+
+func (pt ProjectTime) reportKey(dimension string, loc *time.Location) (string, string) {
+	switch dimension {
+	case "source":
+		return pt.Source, pt.Source
+	case "project":
+		title := pt.ProjectTitle
+		if title == "" {
+			title = "(no project)"
+		}
+		return pt.Source + ":" + pt.ProjectID, title
+	}
+
+	if pt.Datetime == nil {
+		return "undated", "Undated"
+	}
+
+	date := pt.Datetime.In(loc)
+	switch dimension {
+	case "week":
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week), fmt.Sprintf("Week %d, %d", week, year)
+	case "month":
+		return date.Format("2006-01"), date.Format("January 2006")
+	default:
+		return date.Format("2006-01-02"), date.Format("Mon, Jan 2 2006")
+	}
+}
@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+// RunningTimer is a timer currently running on a tracker
+type RunningTimer struct {
+	Source       string    `json:"source"`
+	ProjectID    string    `json:"project_id"`
+	ProjectTitle string    `json:"project_title"`
+	Description  string    `json:"description"`
+	StartedAt    time.Time `json:"started_at"`
+}
+
+// Elapsed returns how many seconds the timer has been running at now
+func (rt *RunningTimer) Elapsed(now time.Time) int {
+	if rt == nil || now.Before(rt.StartedAt) {
+		return 0
+	}
+	return int(now.Sub(rt.StartedAt).Seconds())
+}
+
+// ToMap converts RunningTimer to a map with the elapsed time at now
+func (rt *RunningTimer) ToMap(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"source":          rt.Source,
+		"project_id":      rt.ProjectID,
+		"project_title":   rt.ProjectTitle,
+		"description":     rt.Description,
+		"started_at":      rt.StartedAt,
+		"elapsed_seconds": rt.Elapsed(now),
+	}
+}
@@ -0,0 +1,9 @@
+package types
+
+// WebhookEvent is a change to one time entry pushed by a tracker
+type WebhookEvent struct {
+	Source  string
+	Deleted bool
+	// Entry always has EntryID; the rest is only set for upserts
+	Entry ProjectTime
+}
@@ -0,0 +1,197 @@
+package workdays
+
+import (
+	"sort"
+	"time"
+)
+
+// Holiday is a day off for everyone, from the built-in rules or an
+// imported list. Date is midnight UTC of the calendar day.
+type Holiday struct {
+	Date   time.Time `json:"date"`
+	Name   string    `json:"name"`
+	Source string    `json:"source"`
+}
+
+// Calendar tells working days from days off, knows how much of each
+// working day is taken as leave and what each day's target is. Days are
+// compared by their calendar date, so it works in whatever timezone the days
+// are given in.
+//
+// Schedule profiles decide the working days and their hours from the day
+// they take effect. Before the first profile, the work week and goals
+// decide.
+type Calendar struct {
+	workWeek map[time.Weekday]bool
+	goals    Goals
+	holidays map[string]Holiday
+	leave    map[string][]Leave
+	profiles []Profile
+}
+
+// Goals are the targets before the first schedule profile: each day of the
+// work week is worth Daily hours, less if the month's work-week days would
+// add up to more than Monthly. Holidays don't move hours onto the other
+// days, they just lower the month.
+type Goals struct {
+	Daily   float64
+	Monthly float64
+}
+
+// New builds a calendar; later holidays win when two share a date.
+func New(workWeek []time.Weekday, goals Goals, holidays []Holiday, leave []Leave, profiles []Profile) *Calendar {
+	c := &Calendar{
+		workWeek: make(map[time.Weekday]bool),
+		goals:    goals,
+		holidays: make(map[string]Holiday),
+		leave:    make(map[string][]Leave),
+		profiles: append([]Profile(nil), profiles...),
+	}
+	sort.Slice(c.profiles, func(i, j int) bool {
+		return c.profiles[i].EffectiveFrom.Before(c.profiles[j].EffectiveFrom)
+	})
+	for _, weekday := range workWeek {
+		c.workWeek[weekday] = true
+	}
+	for _, holiday := range holidays {
+		c.holidays[holiday.Date.Format("2006-01-02")] = holiday
+	}
+	for _, entry := range leave {
+		key := entry.Date.Format("2006-01-02")
+		c.leave[key] = append(c.leave[key], entry)
+	}
+	return c
+}
+
+// Holiday returns the holiday on day's date, if any.
+func (c *Calendar) Holiday(day time.Time) (Holiday, bool) {
+	holiday, ok := c.holidays[day.Format("2006-01-02")]
+	return holiday, ok
+}
+
+// Profile returns the schedule profile in effect on day, if any.
+func (c *Calendar) Profile(day time.Time) (Profile, bool) {
+	date := day.Format("2006-01-02")
+	for i := len(c.profiles) - 1; i >= 0; i-- {
+		if c.profiles[i].EffectiveFrom.Format("2006-01-02") <= date {
+			return c.profiles[i], true
+		}
+	}
+	return Profile{}, false
+}
+
+// IsWorkingDay reports whether day is worked by its schedule and not a
+// holiday.
+func (c *Calendar) IsWorkingDay(day time.Time) bool {
+	if profile, ok := c.Profile(day); ok {
+		if profile.Hours[day.Weekday()] <= 0 {
+			return false
+		}
+	} else if !c.workWeek[day.Weekday()] {
+		return false
+	}
+	_, holiday := c.Holiday(day)
+	return !holiday
+}
+
+// WorkingDays counts the working days among the calendar days in
+// [from, to), stepping through them in from's timezone.
+func (c *Calendar) WorkingDays(from, to time.Time) int {
+	count := 0
+	for d := startOfDay(from); d.Before(to); d = d.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(d) {
+			count++
+		}
+	}
+	return count
+}
+
+// Leave returns the leave taken on day's date.
+func (c *Calendar) Leave(day time.Time) []Leave {
+	return c.leave[day.Format("2006-01-02")]
+}
+
+// LeaveFraction is the part of a working day taken as leave, at most 1.
+// Leave on days off takes nothing.
+func (c *Calendar) LeaveFraction(day time.Time) float64 {
+	if !c.IsWorkingDay(day) {
+		return 0
+	}
+	fraction := 0.0
+	for _, entry := range c.Leave(day) {
+		fraction += entry.Fraction
+	}
+	if fraction > 1 {
+		return 1
+	}
+	return fraction
+}
+
+// LeaveDays sums the leave taken on the working days in [from, to).
+func (c *Calendar) LeaveDays(from, to time.Time) float64 {
+	days := 0.0
+	for d := startOfDay(from); d.Before(to); d = d.AddDate(0, 0, 1) {
+		days += c.LeaveFraction(d)
+	}
+	return days
+}
+
+// AvailableDays counts the working days in [from, to) left after leave.
+func (c *Calendar) AvailableDays(from, to time.Time) float64 {
+	return float64(c.WorkingDays(from, to)) - c.LeaveDays(from, to)
+}
+
+// Target is a working day's goal and what it contributes to the month's:
+// its profile's hours, or the daily goal before the first profile, capped
+// by the monthly goal. Days off, holidays included, have none.
+func (c *Calendar) Target(day time.Time) float64 {
+	if !c.IsWorkingDay(day) {
+		return 0
+	}
+	if profile, ok := c.Profile(day); ok {
+		return profile.Hours[day.Weekday()]
+	}
+
+	// The cap is taken over the month's work-week days as if no profile
+	// applied, so a profile starting mid-month doesn't change the days
+	// before it, and holidays count so they lower the month
+	som := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	days := 0
+	for d := som; d.Before(som.AddDate(0, 1, 0)); d = d.AddDate(0, 0, 1) {
+		if c.workWeek[d.Weekday()] {
+			days++
+		}
+	}
+	if c.goals.Monthly > 0 && float64(days)*c.goals.Daily > c.goals.Monthly {
+		return c.goals.Monthly / float64(days)
+	}
+	return c.goals.Daily
+}
+
+// Goal sums the targets of the days in [from, to), whatever months they
+// fall in.
+func (c *Calendar) Goal(from, to time.Time) float64 {
+	total := 0.0
+	for d := startOfDay(from); d.Before(to); d = d.AddDate(0, 0, 1) {
+		total += c.Target(d)
+	}
+	return total
+}
+
+// MonthlyGoal sums the targets of the month starting at som.
+func (c *Calendar) MonthlyGoal(som time.Time) float64 {
+	return c.Goal(som, som.AddDate(0, 1, 0))
+}
+
+// ExpectedHours sums the targets of the days in [from, to) less leave.
+func (c *Calendar) ExpectedHours(from, to time.Time) float64 {
+	expected := 0.0
+	for d := startOfDay(from); d.Before(to); d = d.AddDate(0, 0, 1) {
+		expected += c.Target(d) * (1 - c.LeaveFraction(d))
+	}
+	return expected
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
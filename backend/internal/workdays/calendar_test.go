@@ -0,0 +1,68 @@
+package workdays
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestTarget(t *testing.T) {
+	workWeek := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	holidays := []Holiday{{Date: date(2024, time.January, 1), Name: "New Year's Day"}}
+	leave := []Leave{{Date: date(2024, time.January, 3), Type: LeaveVacation, Fraction: 0.5}}
+	partTime := Profile{EffectiveFrom: date(2024, time.January, 15)}
+	partTime.Hours[time.Monday] = 6
+
+	// January 2024 has 23 weekdays, so 160 hours cap each at 160/23
+	capped := New(workWeek, Goals{Daily: 8, Monthly: 160}, holidays, leave, nil)
+	uncapped := New(workWeek, Goals{Daily: 8, Monthly: 200}, holidays, leave, nil)
+	profiled := New(workWeek, Goals{Daily: 8, Monthly: 160}, holidays, leave, []Profile{partTime})
+
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		day      time.Time
+		want     float64
+	}{
+		{"daily goal under the cap", uncapped, date(2024, time.January, 2), 8},
+		{"daily goal capped by the month", capped, date(2024, time.January, 2), 160.0 / 23},
+		{"holiday", capped, date(2024, time.January, 1), 0},
+		{"weekend", capped, date(2024, time.January, 6), 0},
+		{"leave keeps the target", capped, date(2024, time.January, 3), 160.0 / 23},
+		{"cap of a shorter month", capped, date(2024, time.February, 1), 160.0 / 21},
+		{"before the profile", profiled, date(2024, time.January, 8), 160.0 / 23},
+		{"profile hours", profiled, date(2024, time.January, 15), 6},
+		{"profile day off", profiled, date(2024, time.January, 16), 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.calendar.Target(test.day); math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("Target(%s) = %v, want %v", test.day.Format("2006-01-02"), got, test.want)
+			}
+		})
+	}
+}
+
+func TestGoals(t *testing.T) {
+	workWeek := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	holidays := []Holiday{{Date: date(2024, time.January, 1), Name: "New Year's Day"}}
+	leave := []Leave{{Date: date(2024, time.January, 3), Type: LeaveVacation, Fraction: 0.5}}
+	calendar := New(workWeek, Goals{Daily: 8, Monthly: 160}, holidays, leave, nil)
+
+	som := date(2024, time.January, 1)
+	eom := som.AddDate(0, 1, 0)
+	day := 160.0 / 23
+
+	// The holiday lowers the month rather than moving its hours elsewhere
+	if got, want := calendar.MonthlyGoal(som), 22*day; math.Abs(got-want) > 1e-9 {
+		t.Errorf("MonthlyGoal() = %v, want %v", got, want)
+	}
+	if got, want := calendar.ExpectedHours(som, eom), 21.5*day; math.Abs(got-want) > 1e-9 {
+		t.Errorf("ExpectedHours() = %v, want %v", got, want)
+	}
+}
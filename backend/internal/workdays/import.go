@@ -0,0 +1,103 @@
+package workdays
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseList reads an imported holiday list: a JSON array of
+// {"date": "YYYY-MM-DD", "name": "..."} objects, or an iCalendar file with
+// one all-day VEVENT per holiday.
+func ParseList(body []byte) ([]Holiday, error) {
+	trimmed := bytes.TrimSpace(body)
+	if bytes.HasPrefix(trimmed, []byte("BEGIN:VCALENDAR")) {
+		return parseICS(trimmed)
+	}
+	return parseJSON(trimmed)
+}
+
+func parseJSON(body []byte) ([]Holiday, error) {
+	var items []struct {
+		Date string `json:"date"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("invalid holiday list: %w", err)
+	}
+
+	holidays := make([]Holiday, 0, len(items))
+	for _, item := range items {
+		date, err := time.Parse("2006-01-02", item.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", item.Date)
+		}
+		holidays = append(holidays, Holiday{Date: date, Name: item.Name, Source: "imported"})
+	}
+	return holidays, nil
+}
+
+// parseICS understands the subset of iCalendar public holiday feeds use:
+// DTSTART as a date (optionally with parameters) and SUMMARY. Multi-day
+// events only count their first day.
+func parseICS(body []byte) ([]Holiday, error) {
+	var holidays []Holiday
+	var current *Holiday
+
+	scanner := bufio.NewScanner(bytes.NewReader(unfold(body)))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";")
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if value == "VEVENT" {
+				current = &Holiday{Source: "imported"}
+			}
+		case "DTSTART":
+			if current == nil {
+				continue
+			}
+			if len(value) < 8 {
+				return nil, fmt.Errorf("invalid DTSTART %q", value)
+			}
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTSTART %q", value)
+			}
+			current.Date = date
+		case "SUMMARY":
+			if current != nil {
+				current.Name = strings.ReplaceAll(value, `\,`, ",")
+			}
+		case "END":
+			if value == "VEVENT" && current != nil {
+				if !current.Date.IsZero() {
+					holidays = append(holidays, *current)
+				}
+				current = nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid calendar: %w", err)
+	}
+
+	return holidays, nil
+}
+
+// unfold joins iCalendar continuation lines, which start with a space or
+// tab.
+func unfold(body []byte) []byte {
+	body = bytes.ReplaceAll(body, []byte("\r\n "), nil)
+	body = bytes.ReplaceAll(body, []byte("\r\n\t"), nil)
+	body = bytes.ReplaceAll(body, []byte("\n "), nil)
+	return bytes.ReplaceAll(body, []byte("\n\t"), nil)
+}
@@ -0,0 +1,31 @@
+package workdays
+
+import "time"
+
+const (
+	LeaveVacation = "vacation"
+	LeaveSick     = "sick"
+	LeaveUnpaid   = "unpaid"
+)
+
+// LeaveTypes lists the kinds of leave, in the order balances show them.
+var LeaveTypes = []string{LeaveVacation, LeaveSick, LeaveUnpaid}
+
+// Leave is time off one person takes on one day. Fraction is the part of the
+// day taken, 1 for a full day.
+type Leave struct {
+	ID       uint      `json:"id"`
+	Date     time.Time `json:"date"`
+	Type     string    `json:"type"`
+	Fraction float64   `json:"fraction"`
+	Note     string    `json:"note"`
+}
+
+func IsLeaveType(leaveType string) bool {
+	for _, candidate := range LeaveTypes {
+		if candidate == leaveType {
+			return true
+		}
+	}
+	return false
+}
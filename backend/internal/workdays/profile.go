@@ -0,0 +1,40 @@
+package workdays
+
+import (
+	"strings"
+	"time"
+)
+
+// Profile is a work schedule: the hours worked on each weekday, from
+// EffectiveFrom (midnight UTC of the calendar day) until the next profile
+// starts. A weekday without hours is a day off.
+type Profile struct {
+	ID            uint
+	Name          string
+	EffectiveFrom time.Time
+	Hours         [7]float64
+}
+
+// WeeklyHours sums the hours of the whole week.
+func (p Profile) WeeklyHours() float64 {
+	total := 0.0
+	for _, hours := range p.Hours {
+		total += hours
+	}
+	return total
+}
+
+// ParseWeekday reads a lowercase weekday name such as "monday".
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if WeekdayName(day) == name {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// WeekdayName is the lowercase name ParseWeekday reads.
+func WeekdayName(day time.Weekday) string {
+	return strings.ToLower(day.String())
+}
@@ -0,0 +1,247 @@
+package workdays
+
+import (
+	"sort"
+	"time"
+)
+
+// rule yields the date of one public holiday in a year, or false if the
+// holiday doesn't exist that year.
+type rule struct {
+	name string
+	date func(year int) (time.Time, bool)
+}
+
+// countries holds the built-in nationwide public holidays. Regional ones
+// (German states, Swiss cantons, ...) can be added with an imported list.
+var countries = map[string]struct {
+	name  string
+	rules []rule
+	// observe moves holidays that fall on a weekend to a working day
+	observe func(holidays []Holiday) []Holiday
+}{
+	"AT": {name: "Austria", rules: []rule{
+		fixed("Neujahr", 1, 1),
+		fixed("Heilige Drei Könige", 1, 6),
+		easter("Ostermontag", 1),
+		fixed("Staatsfeiertag", 5, 1),
+		easter("Christi Himmelfahrt", 39),
+		easter("Pfingstmontag", 50),
+		easter("Fronleichnam", 60),
+		fixed("Mariä Himmelfahrt", 8, 15),
+		fixed("Nationalfeiertag", 10, 26),
+		fixed("Allerheiligen", 11, 1),
+		fixed("Mariä Empfängnis", 12, 8),
+		fixed("Christtag", 12, 25),
+		fixed("Stefanitag", 12, 26),
+	}},
+	"DE": {name: "Germany", rules: []rule{
+		fixed("Neujahr", 1, 1),
+		easter("Karfreitag", -2),
+		easter("Ostermontag", 1),
+		fixed("Tag der Arbeit", 5, 1),
+		easter("Christi Himmelfahrt", 39),
+		easter("Pfingstmontag", 50),
+		fixed("Tag der Deutschen Einheit", 10, 3),
+		fixed("1. Weihnachtstag", 12, 25),
+		fixed("2. Weihnachtstag", 12, 26),
+	}},
+	"FR": {name: "France", rules: []rule{
+		fixed("Jour de l'an", 1, 1),
+		easter("Lundi de Pâques", 1),
+		fixed("Fête du Travail", 5, 1),
+		fixed("Victoire 1945", 5, 8),
+		easter("Ascension", 39),
+		easter("Lundi de Pentecôte", 50),
+		fixed("Fête nationale", 7, 14),
+		fixed("Assomption", 8, 15),
+		fixed("Toussaint", 11, 1),
+		fixed("Armistice 1918", 11, 11),
+		fixed("Noël", 12, 25),
+	}},
+	"GB": {name: "United Kingdom (England and Wales)", rules: []rule{
+		fixed("New Year's Day", 1, 1),
+		easter("Good Friday", -2),
+		easter("Easter Monday", 1),
+		nthWeekday("Early May bank holiday", 5, time.Monday, 1),
+		nthWeekday("Spring bank holiday", 5, time.Monday, -1),
+		nthWeekday("Summer bank holiday", 8, time.Monday, -1),
+		fixed("Christmas Day", 12, 25),
+		fixed("Boxing Day", 12, 26),
+	}, observe: substituteNextWorkday},
+	"NL": {name: "Netherlands", rules: []rule{
+		fixed("Nieuwjaarsdag", 1, 1),
+		easter("Tweede Paasdag", 1),
+		kingsDay(),
+		easter("Hemelvaartsdag", 39),
+		easter("Tweede Pinksterdag", 50),
+		fixed("Eerste Kerstdag", 12, 25),
+		fixed("Tweede Kerstdag", 12, 26),
+	}},
+	"PL": {name: "Poland", rules: []rule{
+		fixed("Nowy Rok", 1, 1),
+		fixed("Trzech Króli", 1, 6),
+		easter("Poniedziałek Wielkanocny", 1),
+		fixed("Święto Pracy", 5, 1),
+		fixed("Święto Konstytucji 3 Maja", 5, 3),
+		easter("Boże Ciało", 60),
+		fixed("Wniebowzięcie NMP", 8, 15),
+		fixed("Wszystkich Świętych", 11, 1),
+		fixed("Święto Niepodległości", 11, 11),
+		since(2025, fixed("Wigilia", 12, 24)),
+		fixed("Boże Narodzenie", 12, 25),
+		fixed("Drugi dzień Bożego Narodzenia", 12, 26),
+	}},
+	"US": {name: "United States (federal)", rules: []rule{
+		fixed("New Year's Day", 1, 1),
+		nthWeekday("Martin Luther King Jr. Day", 1, time.Monday, 3),
+		nthWeekday("Washington's Birthday", 2, time.Monday, 3),
+		nthWeekday("Memorial Day", 5, time.Monday, -1),
+		since(2021, fixed("Juneteenth", 6, 19)),
+		fixed("Independence Day", 7, 4),
+		nthWeekday("Labor Day", 9, time.Monday, 1),
+		nthWeekday("Columbus Day", 10, time.Monday, 2),
+		fixed("Veterans Day", 11, 11),
+		nthWeekday("Thanksgiving Day", 11, time.Thursday, 4),
+		fixed("Christmas Day", 12, 25),
+	}, observe: nearestWeekday},
+}
+
+// Countries lists the codes and names of the built-in holiday rules.
+func Countries() map[string]string {
+	result := make(map[string]string, len(countries))
+	for code, country := range countries {
+		result[code] = country.name
+	}
+	return result
+}
+
+// PublicHolidays computes a country's public holidays for a year, sorted
+// by date. Unknown countries have none.
+func PublicHolidays(country string, year int) []Holiday {
+	definition, ok := countries[country]
+	if !ok {
+		return nil
+	}
+
+	var holidays []Holiday
+	for _, r := range definition.rules {
+		if date, ok := r.date(year); ok {
+			holidays = append(holidays, Holiday{Date: date, Name: r.name, Source: country})
+		}
+	}
+	sort.Slice(holidays, func(a, b int) bool {
+		return holidays[a].Date.Before(holidays[b].Date)
+	})
+
+	if definition.observe != nil {
+		holidays = definition.observe(holidays)
+	}
+	return holidays
+}
+
+func fixed(name string, month time.Month, day int) rule {
+	return rule{name: name, date: func(year int) (time.Time, bool) {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), true
+	}}
+}
+
+func easter(name string, offset int) rule {
+	return rule{name: name, date: func(year int) (time.Time, bool) {
+		return easterSunday(year).AddDate(0, 0, offset), true
+	}}
+}
+
+// since limits r to the years from first on, for holidays introduced by
+// law.
+func since(first int, r rule) rule {
+	return rule{name: r.name, date: func(year int) (time.Time, bool) {
+		if year < first {
+			return time.Time{}, false
+		}
+		return r.date(year)
+	}}
+}
+
+// nthWeekday is the n-th given weekday of the month, counting from the end
+// when n is negative.
+func nthWeekday(name string, month time.Month, weekday time.Weekday, n int) rule {
+	return rule{name: name, date: func(year int) (time.Time, bool) {
+		if n < 0 {
+			last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+			back := (int(last.Weekday()) - int(weekday) + 7) % 7
+			return last.AddDate(0, 0, -back+(n+1)*7), true
+		}
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		ahead := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, ahead+(n-1)*7), true
+	}}
+}
+
+// kingsDay is 27 April, or the 26th when the 27th is a Sunday.
+func kingsDay() rule {
+	return rule{name: "Koningsdag", date: func(year int) (time.Time, bool) {
+		date := time.Date(year, time.April, 27, 0, 0, 0, 0, time.UTC)
+		if date.Weekday() == time.Sunday {
+			date = date.AddDate(0, 0, -1)
+		}
+		return date, true
+	}}
+}
+
+// easterSunday uses the anonymous Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// nearestWeekday moves Saturday holidays to Friday and Sunday holidays to
+// Monday, as US federal holidays are observed.
+func nearestWeekday(holidays []Holiday) []Holiday {
+	for i, holiday := range holidays {
+		switch holiday.Date.Weekday() {
+		case time.Saturday:
+			holidays[i].Date = holiday.Date.AddDate(0, 0, -1)
+		case time.Sunday:
+			holidays[i].Date = holiday.Date.AddDate(0, 0, 1)
+		}
+	}
+	return holidays
+}
+
+// substituteNextWorkday moves weekend holidays to the next weekday that
+// isn't already a holiday, like UK substitute bank holidays.
+func substituteNextWorkday(holidays []Holiday) []Holiday {
+	taken := make(map[string]bool)
+	for _, holiday := range holidays {
+		if weekday := holiday.Date.Weekday(); weekday != time.Saturday && weekday != time.Sunday {
+			taken[holiday.Date.Format("2006-01-02")] = true
+		}
+	}
+
+	for i, holiday := range holidays {
+		date := holiday.Date
+		if weekday := date.Weekday(); weekday != time.Saturday && weekday != time.Sunday {
+			continue
+		}
+		for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday || taken[date.Format("2006-01-02")] {
+			date = date.AddDate(0, 0, 1)
+		}
+		taken[date.Format("2006-01-02")] = true
+		holidays[i].Date = date
+	}
+	return holidays
+}
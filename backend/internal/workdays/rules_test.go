@@ -0,0 +1,62 @@
+package workdays
+
+import "testing"
+
+func TestPublicHolidays(t *testing.T) {
+	tests := []struct {
+		country string
+		year    int
+		name    string
+		// date is where the holiday is observed, empty when it isn't one
+		date string
+	}{
+		{"DE", 2024, "Karfreitag", "2024-03-29"},
+		{"DE", 2024, "Ostermontag", "2024-04-01"},
+		{"DE", 2024, "Christi Himmelfahrt", "2024-05-09"},
+		{"DE", 2025, "Karfreitag", "2025-04-18"},
+		{"DE", 2025, "Ostermontag", "2025-04-21"},
+		{"DE", 2025, "Pfingstmontag", "2025-06-09"},
+		{"AT", 2024, "Fronleichnam", "2024-05-30"},
+		{"AT", 2025, "Fronleichnam", "2025-06-19"},
+		{"FR", 2024, "Lundi de Pentecôte", "2024-05-20"},
+		{"NL", 2024, "Koningsdag", "2024-04-27"},
+		{"NL", 2025, "Koningsdag", "2025-04-26"},
+		{"PL", 2024, "Wigilia", ""},
+		{"PL", 2025, "Wigilia", "2025-12-24"},
+		{"PL", 2025, "Boże Ciało", "2025-06-19"},
+		{"GB", 2024, "Early May bank holiday", "2024-05-06"},
+		{"GB", 2024, "Spring bank holiday", "2024-05-27"},
+		{"GB", 2024, "Summer bank holiday", "2024-08-26"},
+		{"GB", 2021, "Christmas Day", "2021-12-27"},
+		{"GB", 2021, "Boxing Day", "2021-12-28"},
+		{"GB", 2022, "New Year's Day", "2022-01-03"},
+		{"GB", 2022, "Boxing Day", "2022-12-26"},
+		{"GB", 2022, "Christmas Day", "2022-12-27"},
+		{"US", 2020, "Juneteenth", ""},
+		{"US", 2021, "Juneteenth", "2021-06-18"},
+		{"US", 2022, "Juneteenth", "2022-06-20"},
+		{"US", 2021, "Independence Day", "2021-07-05"},
+		{"US", 2026, "Independence Day", "2026-07-03"},
+		{"US", 2025, "Martin Luther King Jr. Day", "2025-01-20"},
+		{"US", 2024, "Memorial Day", "2024-05-27"},
+		{"US", 2024, "Thanksgiving Day", "2024-11-28"},
+	}
+
+	for _, test := range tests {
+		got := ""
+		for _, holiday := range PublicHolidays(test.country, test.year) {
+			if holiday.Name == test.name {
+				got = holiday.Date.Format("2006-01-02")
+			}
+		}
+		if got != test.date {
+			t.Errorf("%s %d %s = %q, want %q", test.country, test.year, test.name, got, test.date)
+		}
+	}
+}
+
+func TestPublicHolidaysUnknownCountry(t *testing.T) {
+	if holidays := PublicHolidays("XX", 2024); holidays != nil {
+		t.Errorf("PublicHolidays(XX) = %v, want none", holidays)
+	}
+}
@@ -5,7 +5,7 @@ import { useParams } from 'next/navigation'
 import Navigation from '@/components/Navigation'
 import TodayCard from '@/components/today/TodayCard'
 import MonthCard from '@/components/today/MonthCard'
-import PaceCard from '@/components/today/PaceCard'
+import ForecastCard, { Forecast } from '@/components/today/ForecastCard'
 import api from '@/lib/api'
 
 interface TodayData {
@@ -15,7 +15,7 @@ interface TodayData {
   today_percent: number
   month_percent: number
   month_hours: number
-  pace: number
+  forecast: Forecast
   daily_goal: number
   nav: {
     month: string
@@ -93,7 +93,7 @@ export default function TodayPage() {
             todayPercent={data.today_percent}
           />
           <MonthCard monthPercent={data.month_percent} monthHours={data.month_hours} />
-          <PaceCard pace={data.pace} dailyGoal={data.daily_goal} />
+          <ForecastCard forecast={data.forecast} dailyGoal={data.daily_goal} />
         </div>
       </div>
 
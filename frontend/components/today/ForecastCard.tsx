@@ -0,0 +1,38 @@
+import { hoursToString } from '@/lib/helpers'
+
+export interface Forecast {
+  hours: number
+  low: number
+  high: number
+  goal: number
+  reaches_goal: boolean
+  goal_date: string | null
+}
+
+interface ForecastCardProps {
+  forecast: Forecast
+  dailyGoal: number
+}
+
+export default function ForecastCard({ forecast, dailyGoal }: ForecastCardProps) {
+  const forecastClass = () => {
+    if (forecast.reaches_goal) return 'text-green-600'
+    if (forecast.hours < forecast.goal - dailyGoal) return 'text-red-600'
+    return ''
+  }
+
+  return (
+    <div>
+      <div className="text-gray-600">Forecast</div>
+      <div className={`mt-4 ${forecastClass()}`}>
+        {hoursToString(forecast.hours)}
+      </div>
+      <div className="text-sm text-gray-600">
+        {hoursToString(forecast.low)}–{hoursToString(forecast.high)}
+      </div>
+      {forecast.goal_date && (
+        <div className="text-sm text-gray-600">goal {forecast.goal_date.slice(5)}</div>
+      )}
+    </div>
+  )
+}